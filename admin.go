@@ -0,0 +1,183 @@
+package sloggergo
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// LevelHandler is an http.Handler for inspecting and changing a logger's
+// levels at runtime, meant to be mounted on an admin mux:
+//
+//	mux.Handle("/debug/loglevel", sloggergo.NewLevelHandler(logger))
+//
+// GET returns the current level and per-name levels:
+//
+//	{"level":"INFO","levels":{"db":"DEBUG"}}
+//
+// PUT or POST changes them. A null per-name level removes the override,
+// and an optional ttl reverts every change in the request once it expires:
+//
+//	{"level":"debug","levels":{"db":"debug","http":null},"ttl":"10m"}
+//
+// Changes go through the logger's LevelVar and per-name levels, so they
+// reach every logger sharing them without locking on the logging path.
+// Per-name levels are always shared with derived loggers. The minimum
+// level is shared with loggers derived after NewLevelHandler is called,
+// or with every logger built with the same WithLevelVar; loggers derived
+// before that keep a snapshot of their own, so create the handler first.
+type LevelHandler struct {
+	logger *Logger
+
+	mu      sync.Mutex
+	pending map[string]*levelRevert
+}
+
+// levelRevert restores a level changed with a ttl.
+type levelRevert struct {
+	timer   *time.Timer
+	expires time.Time
+	level   Level
+	existed bool
+}
+
+// levelState is the JSON body of LevelHandler responses.
+type levelState struct {
+	Level     Level                `json:"level"`
+	Levels    map[string]Level     `json:"levels,omitempty"`
+	Temporary map[string]time.Time `json:"temporary,omitempty"`
+}
+
+// levelRequest is the JSON body of LevelHandler updates.
+type levelRequest struct {
+	Level  *Level            `json:"level"`
+	Levels map[string]*Level `json:"levels"`
+	TTL    string            `json:"ttl"`
+}
+
+// rootLevelKey identifies the logger's own level in pending reverts;
+// per-name levels are keyed by name with a leading dot, which no name has.
+const rootLevelKey = ""
+
+// NewLevelHandler creates a LevelHandler for the given logger. From then
+// on, loggers derived from it with With, WithGroup or Named share its
+// level, as if it had been created with WithLevelVar, so that a change to
+// the root level reaches them.
+func NewLevelHandler(logger *Logger) *LevelHandler {
+	logger.mu.Lock()
+	logger.sharedLevel = true
+	logger.mu.Unlock()
+
+	return &LevelHandler{
+		logger:  logger,
+		pending: make(map[string]*levelRevert),
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *LevelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut, http.MethodPost:
+		var req levelRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		var ttl time.Duration
+		if req.TTL != "" {
+			d, err := time.ParseDuration(req.TTL)
+			if err != nil || d <= 0 {
+				http.Error(w, "invalid ttl: "+req.TTL, http.StatusBadRequest)
+				return
+			}
+			ttl = d
+		}
+		h.apply(req, ttl)
+	default:
+		w.Header().Set("Allow", "GET, PUT, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(h.state())
+}
+
+func (h *LevelHandler) state() levelState {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	st := levelState{Level: h.logger.GetLevel()}
+	if levels := h.logger.NamedLevels(); len(levels) > 0 {
+		st.Levels = levels
+	}
+	for key, rv := range h.pending {
+		if st.Temporary == nil {
+			st.Temporary = make(map[string]time.Time)
+		}
+		name := key
+		if key != rootLevelKey {
+			name = key[1:]
+		}
+		st.Temporary[name] = rv.expires
+	}
+	return st
+}
+
+func (h *LevelHandler) apply(req levelRequest, ttl time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if req.Level != nil {
+		h.track(rootLevelKey, ttl, h.logger.GetLevel(), true)
+		h.logger.SetLevel(*req.Level)
+	}
+	for name, lvl := range req.Levels {
+		prev, existed := h.logger.NamedLevels()[name]
+		h.track("."+name, ttl, prev, existed)
+		if lvl == nil {
+			h.logger.ResetNamedLevel(name)
+		} else {
+			h.logger.SetNamedLevel(name, *lvl)
+		}
+	}
+}
+
+// track schedules a revert for key when ttl is set and cancels any earlier
+// one otherwise. A change made while a revert is pending keeps the original
+// level, so the revert always goes back to where the first change started.
+func (h *LevelHandler) track(key string, ttl time.Duration, prev Level, existed bool) {
+	if rv, ok := h.pending[key]; ok {
+		rv.timer.Stop()
+		delete(h.pending, key)
+		prev, existed = rv.level, rv.existed
+	}
+	if ttl == 0 {
+		return
+	}
+
+	rv := &levelRevert{expires: time.Now().Add(ttl), level: prev, existed: existed}
+	rv.timer = time.AfterFunc(ttl, func() { h.revert(key, rv) })
+	h.pending[key] = rv
+}
+
+func (h *LevelHandler) revert(key string, rv *levelRevert) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.pending[key] != rv {
+		return
+	}
+	delete(h.pending, key)
+
+	switch {
+	case key == rootLevelKey:
+		h.logger.SetLevel(rv.level)
+	case rv.existed:
+		h.logger.SetNamedLevel(key[1:], rv.level)
+	default:
+		h.logger.ResetNamedLevel(key[1:])
+	}
+}
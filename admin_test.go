@@ -0,0 +1,94 @@
+package sloggergo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLevelHandler(t *testing.T) {
+	log := New(WithLevel(InfoLevel))
+	h := NewLevelHandler(log)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"level":"warn","levels":{"db":"debug"}}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	if log.GetLevel() != WarnLevel || log.NamedLevels()["db"] != DebugLevel {
+		t.Errorf("expected levels to change, got %v %v", log.GetLevel(), log.NamedLevels())
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	var st struct {
+		Level  string            `json:"level"`
+		Levels map[string]string `json:"levels"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &st); err != nil {
+		t.Fatalf("invalid response %q: %v", rec.Body, err)
+	}
+	if st.Level != "WARN" || st.Levels["db"] != "DEBUG" {
+		t.Errorf("unexpected state %+v", st)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"levels":{"db":null}}`)))
+	if _, ok := log.NamedLevels()["db"]; ok {
+		t.Error("expected null to remove the db override")
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"level":"loud"}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an unknown level, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", rec.Code)
+	}
+}
+
+func TestLevelHandlerTTL(t *testing.T) {
+	log := New(WithLevel(InfoLevel))
+	h := NewLevelHandler(log)
+
+	body := `{"level":"debug","levels":{"db":"trace"},"ttl":"20ms"}`
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPut, "/", strings.NewReader(body)))
+	if log.GetLevel() != DebugLevel {
+		t.Fatalf("expected temporary debug level, got %v", log.GetLevel())
+	}
+
+	reverted := func() bool {
+		_, ok := log.NamedLevels()["db"]
+		return log.GetLevel() == InfoLevel && !ok
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for !reverted() && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !reverted() {
+		t.Errorf("expected levels to revert, got %v %v", log.GetLevel(), log.NamedLevels())
+	}
+}
+
+func TestLevelHandlerReachesDerivedLoggers(t *testing.T) {
+	mock := &mockSink{}
+	log := New(WithLevel(InfoLevel), WithSink(mock))
+	h := NewLevelHandler(log)
+	db := log.Named("db").With("pool", "main")
+	grouped := log.WithGroup("http")
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"level":"debug"}`)))
+
+	db.Debug("query")
+	grouped.Debug("request")
+	if mock.Len() != 2 {
+		t.Errorf("expected derived loggers to follow the root level, got %d entries", mock.Len())
+	}
+}
@@ -2,8 +2,11 @@ package sloggergo
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/godeh/sloggergo/formatter"
@@ -12,7 +15,8 @@ import (
 // AsyncLogger wraps a Logger with async capabilities.
 type AsyncLogger struct {
 	*Logger
-	buffer          chan *formatter.Entry
+	buffer          chan asyncEntry
+	pending         atomic.Int64 // entries queued or being written
 	wg              sync.WaitGroup
 	closed          bool
 	closeMu         sync.Mutex
@@ -73,7 +77,7 @@ func NewAsync(logger *Logger, opts ...AsyncOption) *AsyncLogger {
 		opt(a)
 	}
 
-	a.buffer = make(chan *formatter.Entry, a.bufferSize)
+	a.buffer = make(chan asyncEntry, a.bufferSize)
 
 	// Start workers
 	for i := 0; i < a.workers; i++ {
@@ -87,65 +91,72 @@ func NewAsync(logger *Logger, opts ...AsyncOption) *AsyncLogger {
 func (a *AsyncLogger) worker() {
 	defer a.wg.Done()
 
-	for entry := range a.buffer {
-		a.Logger.mu.RLock()
-		sinks := a.Logger.sinks
-		errorHandler := a.Logger.errorHandler
-		a.Logger.mu.RUnlock()
-
-		for _, s := range sinks {
-			if err := s.Write(entry); err != nil {
-				if errorHandler != nil {
-					errorHandler(err)
-				}
-			}
-		}
+	for e := range a.buffer {
+		a.Logger.writeEntry(e.entry.Context, e.level, e.entry)
+		releaseEntry(e.entry)
+		a.pending.Add(-1)
 	}
 }
 
+// asyncEntry is an entry waiting to be written, with the level that
+// post-write hooks filter on.
+type asyncEntry struct {
+	entry *formatter.Entry
+	level Level
+}
+
 // logAsync sends log entry to buffer without blocking.
 func (a *AsyncLogger) logAsync(ctx context.Context, level Level, msg string, keyvals ...slog.Attr) {
-	a.Logger.mu.RLock()
-	if level < a.Logger.level {
-		a.Logger.mu.RUnlock()
+	if !a.Logger.enabled(level) {
 		return
 	}
-	a.Logger.mu.RUnlock()
-
-	// Build entry
-	fields := make(map[string]any)
-	a.Logger.mu.RLock()
-	for k, v := range a.Logger.fields {
-		fields[k] = v
-	}
-	a.Logger.mu.RUnlock()
 
-	for _, val := range keyvals {
-		fields[val.Key] = val.Value.Any()
-	}
-
-	caller := ""
+	var caller runtime.Frame
 	if a.Logger.addCaller {
-		caller = getCaller(3)
+		caller = a.Logger.callerFrame(3)
 	}
 
-	entry := &formatter.Entry{
-		Time:    time.Now().Format(time.RFC3339Nano),
-		Level:   level.String(),
-		Message: msg,
-		Fields:  fields,
-		Caller:  caller,
-		Context: ctx,
+	entry := a.Logger.newEntry(ctx, level, time.Now(), msg, caller, keyvals)
+
+	// Hooks run on the caller's goroutine, while ctx is still live.
+	if !a.Logger.runHooks(ctx, level, entry) {
+		releaseEntry(entry)
+		return
 	}
+	a.Logger.applyLimits(entry)
 
 	// Non-blocking send
+	a.pending.Add(1)
 	select {
-	case a.buffer <- entry:
+	case a.buffer <- asyncEntry{entry, level}:
 	default:
 		// Buffer full, drop log (or could count dropped)
+		a.pending.Add(-1)
+		releaseEntry(entry)
 	}
 }
 
+// Log logs a message at any level asynchronously.
+func (a *AsyncLogger) Log(ctx context.Context, level Level, msg string, keyvals ...slog.Attr) {
+	if level >= PanicLevel {
+		// Panic and Fatal run synchronously to ensure they're written
+		a.Flush()
+		a.Logger.log(ctx, level, msg, keyvals...)
+		return
+	}
+	a.logAsync(ctx, level, msg, keyvals...)
+}
+
+// Trace logs a trace message asynchronously.
+func (a *AsyncLogger) Trace(msg string, keyvals ...slog.Attr) {
+	a.logAsync(context.Background(), TraceLevel, msg, keyvals...)
+}
+
+// TraceContext logs a trace message asynchronously with context.
+func (a *AsyncLogger) TraceContext(ctx context.Context, msg string, keyvals ...slog.Attr) {
+	a.logAsync(ctx, TraceLevel, msg, keyvals...)
+}
+
 // Debug logs a debug message asynchronously.
 func (a *AsyncLogger) Debug(msg string, keyvals ...slog.Attr) {
 	a.logAsync(context.Background(), DebugLevel, msg, keyvals...)
@@ -166,6 +177,16 @@ func (a *AsyncLogger) InfoContext(ctx context.Context, msg string, keyvals ...sl
 	a.logAsync(ctx, InfoLevel, msg, keyvals...)
 }
 
+// Notice logs a notice message asynchronously.
+func (a *AsyncLogger) Notice(msg string, keyvals ...slog.Attr) {
+	a.logAsync(context.Background(), NoticeLevel, msg, keyvals...)
+}
+
+// NoticeContext logs a notice message asynchronously with context.
+func (a *AsyncLogger) NoticeContext(ctx context.Context, msg string, keyvals ...slog.Attr) {
+	a.logAsync(ctx, NoticeLevel, msg, keyvals...)
+}
+
 // Warn logs a warning message asynchronously.
 func (a *AsyncLogger) Warn(msg string, keyvals ...slog.Attr) {
 	a.logAsync(context.Background(), WarnLevel, msg, keyvals...)
@@ -186,16 +207,166 @@ func (a *AsyncLogger) ErrorContext(ctx context.Context, msg string, keyvals ...s
 	a.logAsync(ctx, ErrorLevel, msg, keyvals...)
 }
 
+// Critical logs a critical message asynchronously.
+func (a *AsyncLogger) Critical(msg string, keyvals ...slog.Attr) {
+	a.logAsync(context.Background(), CriticalLevel, msg, keyvals...)
+}
+
+// CriticalContext logs a critical message asynchronously with context.
+func (a *AsyncLogger) CriticalContext(ctx context.Context, msg string, keyvals ...slog.Attr) {
+	a.logAsync(ctx, CriticalLevel, msg, keyvals...)
+}
+
+// Panic logs a panic message and panics (runs synchronously for safety).
+func (a *AsyncLogger) Panic(msg string, keyvals ...slog.Attr) {
+	a.Flush()
+	a.Logger.log(context.Background(), PanicLevel, msg, keyvals...)
+}
+
 // Fatal logs a fatal message (runs synchronously for safety).
+// Buffered entries are written before the logger exits.
 func (a *AsyncLogger) Fatal(msg string, keyvals ...slog.Attr) {
 	// Fatal runs synchronously to ensure it's written
-	a.Logger.Fatal(msg, keyvals...)
+	a.Flush()
+	a.Logger.log(context.Background(), FatalLevel, msg, keyvals...)
+}
+
+// Logf logs a formatted message at any level asynchronously.
+// Panic and Fatal run synchronously, as with Log.
+func (a *AsyncLogger) Logf(ctx context.Context, level Level, format string, args ...any) {
+	if level >= PanicLevel {
+		a.Flush()
+		a.Logger.log(ctx, level, fmt.Sprintf(format, args...))
+		return
+	}
+	if a.Logger.enabled(level) {
+		a.logAsync(ctx, level, fmt.Sprintf(format, args...))
+	}
+}
+
+// Logw logs a message at any level with alternating keys and values
+// asynchronously. Panic and Fatal run synchronously, as with Log.
+func (a *AsyncLogger) Logw(ctx context.Context, level Level, msg string, keysAndValues ...any) {
+	if level >= PanicLevel {
+		a.Flush()
+		a.Logger.log(ctx, level, msg, argsToAttrs(keysAndValues)...)
+		return
+	}
+	a.logAsync(ctx, level, msg, argsToAttrs(keysAndValues)...)
+}
+
+// Tracef logs a formatted trace message asynchronously.
+func (a *AsyncLogger) Tracef(format string, args ...any) {
+	if a.Logger.enabled(TraceLevel) {
+		a.logAsync(context.Background(), TraceLevel, fmt.Sprintf(format, args...))
+	}
+}
+
+// Tracew logs a trace message with alternating keys and values asynchronously.
+func (a *AsyncLogger) Tracew(msg string, keysAndValues ...any) {
+	a.logAsync(context.Background(), TraceLevel, msg, argsToAttrs(keysAndValues)...)
+}
+
+// Debugf logs a formatted debug message asynchronously.
+func (a *AsyncLogger) Debugf(format string, args ...any) {
+	if a.Logger.enabled(DebugLevel) {
+		a.logAsync(context.Background(), DebugLevel, fmt.Sprintf(format, args...))
+	}
+}
+
+// Debugw logs a debug message with alternating keys and values asynchronously.
+func (a *AsyncLogger) Debugw(msg string, keysAndValues ...any) {
+	a.logAsync(context.Background(), DebugLevel, msg, argsToAttrs(keysAndValues)...)
+}
+
+// Infof logs a formatted info message asynchronously.
+func (a *AsyncLogger) Infof(format string, args ...any) {
+	if a.Logger.enabled(InfoLevel) {
+		a.logAsync(context.Background(), InfoLevel, fmt.Sprintf(format, args...))
+	}
+}
+
+// Infow logs an info message with alternating keys and values asynchronously.
+func (a *AsyncLogger) Infow(msg string, keysAndValues ...any) {
+	a.logAsync(context.Background(), InfoLevel, msg, argsToAttrs(keysAndValues)...)
+}
+
+// Noticef logs a formatted notice message asynchronously.
+func (a *AsyncLogger) Noticef(format string, args ...any) {
+	if a.Logger.enabled(NoticeLevel) {
+		a.logAsync(context.Background(), NoticeLevel, fmt.Sprintf(format, args...))
+	}
+}
+
+// Noticew logs a notice message with alternating keys and values asynchronously.
+func (a *AsyncLogger) Noticew(msg string, keysAndValues ...any) {
+	a.logAsync(context.Background(), NoticeLevel, msg, argsToAttrs(keysAndValues)...)
+}
+
+// Warnf logs a formatted warning message asynchronously.
+func (a *AsyncLogger) Warnf(format string, args ...any) {
+	if a.Logger.enabled(WarnLevel) {
+		a.logAsync(context.Background(), WarnLevel, fmt.Sprintf(format, args...))
+	}
+}
+
+// Warnw logs a warning message with alternating keys and values asynchronously.
+func (a *AsyncLogger) Warnw(msg string, keysAndValues ...any) {
+	a.logAsync(context.Background(), WarnLevel, msg, argsToAttrs(keysAndValues)...)
+}
+
+// Errorf logs a formatted error message asynchronously.
+func (a *AsyncLogger) Errorf(format string, args ...any) {
+	if a.Logger.enabled(ErrorLevel) {
+		a.logAsync(context.Background(), ErrorLevel, fmt.Sprintf(format, args...))
+	}
+}
+
+// Errorw logs an error message with alternating keys and values asynchronously.
+func (a *AsyncLogger) Errorw(msg string, keysAndValues ...any) {
+	a.logAsync(context.Background(), ErrorLevel, msg, argsToAttrs(keysAndValues)...)
+}
+
+// Criticalf logs a formatted critical message asynchronously.
+func (a *AsyncLogger) Criticalf(format string, args ...any) {
+	if a.Logger.enabled(CriticalLevel) {
+		a.logAsync(context.Background(), CriticalLevel, fmt.Sprintf(format, args...))
+	}
+}
+
+// Criticalw logs a critical message with alternating keys and values asynchronously.
+func (a *AsyncLogger) Criticalw(msg string, keysAndValues ...any) {
+	a.logAsync(context.Background(), CriticalLevel, msg, argsToAttrs(keysAndValues)...)
+}
+
+// Panicf logs a formatted panic message (runs synchronously for safety).
+func (a *AsyncLogger) Panicf(format string, args ...any) {
+	a.Flush()
+	a.Logger.log(context.Background(), PanicLevel, fmt.Sprintf(format, args...))
+}
+
+// Panicw logs a panic message with alternating keys and values (runs synchronously for safety).
+func (a *AsyncLogger) Panicw(msg string, keysAndValues ...any) {
+	a.Flush()
+	a.Logger.log(context.Background(), PanicLevel, msg, argsToAttrs(keysAndValues)...)
+}
+
+// Fatalf logs a formatted fatal message (runs synchronously for safety).
+func (a *AsyncLogger) Fatalf(format string, args ...any) {
+	a.Flush()
+	a.Logger.log(context.Background(), FatalLevel, fmt.Sprintf(format, args...))
 }
 
-// Flush waits for all buffered logs to be written.
+// Fatalw logs a fatal message with alternating keys and values (runs synchronously for safety).
+func (a *AsyncLogger) Fatalw(msg string, keysAndValues ...any) {
+	a.Flush()
+	a.Logger.log(context.Background(), FatalLevel, msg, argsToAttrs(keysAndValues)...)
+}
+
+// Flush waits until every buffered entry has been written, including
+// entries a worker has already taken from the buffer.
 func (a *AsyncLogger) Flush() {
-	// Wait for buffer to drain
-	for len(a.buffer) > 0 {
+	for a.pending.Load() > 0 {
 		time.Sleep(10 * time.Millisecond)
 	}
 }
@@ -293,33 +464,171 @@ func (s *SampledLogger) shouldLog(key string) bool {
 	return false
 }
 
+// Trace logs with sampling.
+func (s *SampledLogger) Trace(msg string, keyvals ...slog.Attr) {
+	if s.shouldLog(msg) {
+		s.Logger.log(context.Background(), TraceLevel, msg, keyvals...)
+	}
+}
+
 // Info logs with sampling.
 func (s *SampledLogger) Info(msg string, keyvals ...slog.Attr) {
 	if s.shouldLog(msg) {
-		s.Logger.Info(msg, keyvals...)
+		s.Logger.log(context.Background(), InfoLevel, msg, keyvals...)
 	}
 }
 
 // Warn logs with sampling.
 func (s *SampledLogger) Warn(msg string, keyvals ...slog.Attr) {
 	if s.shouldLog(msg) {
-		s.Logger.Warn(msg, keyvals...)
+		s.Logger.log(context.Background(), WarnLevel, msg, keyvals...)
+	}
+}
+
+// Notice logs with sampling.
+func (s *SampledLogger) Notice(msg string, keyvals ...slog.Attr) {
+	if s.shouldLog(msg) {
+		s.Logger.log(context.Background(), NoticeLevel, msg, keyvals...)
 	}
 }
 
 // Debug logs with sampling.
 func (s *SampledLogger) Debug(msg string, keyvals ...slog.Attr) {
 	if s.shouldLog(msg) {
-		s.Logger.Debug(msg, keyvals...)
+		s.Logger.log(context.Background(), DebugLevel, msg, keyvals...)
 	}
 }
 
 // Error always logs (no sampling for errors).
 func (s *SampledLogger) Error(msg string, keyvals ...slog.Attr) {
-	s.Logger.Error(msg, keyvals...)
+	s.Logger.log(context.Background(), ErrorLevel, msg, keyvals...)
+}
+
+// Critical always logs (no sampling for critical).
+func (s *SampledLogger) Critical(msg string, keyvals ...slog.Attr) {
+	s.Logger.log(context.Background(), CriticalLevel, msg, keyvals...)
+}
+
+// Panic always logs (no sampling for panic).
+func (s *SampledLogger) Panic(msg string, keyvals ...slog.Attr) {
+	s.Logger.log(context.Background(), PanicLevel, msg, keyvals...)
 }
 
 // Fatal always logs (no sampling for fatal).
 func (s *SampledLogger) Fatal(msg string, keyvals ...slog.Attr) {
-	s.Logger.Fatal(msg, keyvals...)
+	s.Logger.log(context.Background(), FatalLevel, msg, keyvals...)
+}
+
+// Tracef logs with sampling, keyed by the format string.
+func (s *SampledLogger) Tracef(format string, args ...any) {
+	if s.shouldLog(format) && s.Logger.enabled(TraceLevel) {
+		s.Logger.log(context.Background(), TraceLevel, fmt.Sprintf(format, args...))
+	}
+}
+
+// Tracew logs with sampling.
+func (s *SampledLogger) Tracew(msg string, keysAndValues ...any) {
+	if s.shouldLog(msg) {
+		s.Logger.log(context.Background(), TraceLevel, msg, argsToAttrs(keysAndValues)...)
+	}
+}
+
+// Debugf logs with sampling, keyed by the format string.
+func (s *SampledLogger) Debugf(format string, args ...any) {
+	if s.shouldLog(format) && s.Logger.enabled(DebugLevel) {
+		s.Logger.log(context.Background(), DebugLevel, fmt.Sprintf(format, args...))
+	}
+}
+
+// Debugw logs with sampling.
+func (s *SampledLogger) Debugw(msg string, keysAndValues ...any) {
+	if s.shouldLog(msg) {
+		s.Logger.log(context.Background(), DebugLevel, msg, argsToAttrs(keysAndValues)...)
+	}
+}
+
+// Infof logs with sampling, keyed by the format string.
+func (s *SampledLogger) Infof(format string, args ...any) {
+	if s.shouldLog(format) && s.Logger.enabled(InfoLevel) {
+		s.Logger.log(context.Background(), InfoLevel, fmt.Sprintf(format, args...))
+	}
+}
+
+// Infow logs with sampling.
+func (s *SampledLogger) Infow(msg string, keysAndValues ...any) {
+	if s.shouldLog(msg) {
+		s.Logger.log(context.Background(), InfoLevel, msg, argsToAttrs(keysAndValues)...)
+	}
+}
+
+// Noticef logs with sampling, keyed by the format string.
+func (s *SampledLogger) Noticef(format string, args ...any) {
+	if s.shouldLog(format) && s.Logger.enabled(NoticeLevel) {
+		s.Logger.log(context.Background(), NoticeLevel, fmt.Sprintf(format, args...))
+	}
+}
+
+// Noticew logs with sampling.
+func (s *SampledLogger) Noticew(msg string, keysAndValues ...any) {
+	if s.shouldLog(msg) {
+		s.Logger.log(context.Background(), NoticeLevel, msg, argsToAttrs(keysAndValues)...)
+	}
+}
+
+// Warnf logs with sampling, keyed by the format string.
+func (s *SampledLogger) Warnf(format string, args ...any) {
+	if s.shouldLog(format) && s.Logger.enabled(WarnLevel) {
+		s.Logger.log(context.Background(), WarnLevel, fmt.Sprintf(format, args...))
+	}
+}
+
+// Warnw logs with sampling.
+func (s *SampledLogger) Warnw(msg string, keysAndValues ...any) {
+	if s.shouldLog(msg) {
+		s.Logger.log(context.Background(), WarnLevel, msg, argsToAttrs(keysAndValues)...)
+	}
+}
+
+// Errorf always logs (no sampling for error).
+func (s *SampledLogger) Errorf(format string, args ...any) {
+	if s.Logger.enabled(ErrorLevel) {
+		s.Logger.log(context.Background(), ErrorLevel, fmt.Sprintf(format, args...))
+	}
+}
+
+// Errorw always logs (no sampling for error).
+func (s *SampledLogger) Errorw(msg string, keysAndValues ...any) {
+	s.Logger.log(context.Background(), ErrorLevel, msg, argsToAttrs(keysAndValues)...)
+}
+
+// Criticalf always logs (no sampling for critical).
+func (s *SampledLogger) Criticalf(format string, args ...any) {
+	if s.Logger.enabled(CriticalLevel) {
+		s.Logger.log(context.Background(), CriticalLevel, fmt.Sprintf(format, args...))
+	}
+}
+
+// Criticalw always logs (no sampling for critical).
+func (s *SampledLogger) Criticalw(msg string, keysAndValues ...any) {
+	s.Logger.log(context.Background(), CriticalLevel, msg, argsToAttrs(keysAndValues)...)
+}
+
+// Panicf always logs (no sampling for panic).
+func (s *SampledLogger) Panicf(format string, args ...any) {
+	s.Logger.log(context.Background(), PanicLevel, fmt.Sprintf(format, args...))
+}
+
+// Panicw always logs (no sampling for panic).
+func (s *SampledLogger) Panicw(msg string, keysAndValues ...any) {
+	s.Logger.log(context.Background(), PanicLevel, msg, argsToAttrs(keysAndValues)...)
+}
+
+// Fatalf always logs (no sampling for fatal).
+func (s *SampledLogger) Fatalf(format string, args ...any) {
+	s.Logger.log(context.Background(), FatalLevel, fmt.Sprintf(format, args...))
+}
+
+// Fatalw always logs (no sampling for fatal).
+func (s *SampledLogger) Fatalw(msg string, keysAndValues ...any) {
+	s.Logger.log(context.Background(), FatalLevel, msg, argsToAttrs(keysAndValues)...)
 }
@@ -0,0 +1,87 @@
+package sloggergo
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/godeh/sloggergo/formatter"
+	"github.com/godeh/sloggergo/sink"
+)
+
+func benchLogger(f formatter.Formatter) *Logger {
+	return New(
+		WithSink(sink.NewStdout(sink.WithWriter(io.Discard), sink.WithFormatter(f))),
+		WithCaller(false),
+	)
+}
+
+func BenchmarkLogger(b *testing.B) {
+	formatters := []struct {
+		name string
+		f    formatter.Formatter
+	}{
+		{"JSON", formatter.NewJSON()},
+		{"Text", formatter.NewTextNoColor()},
+	}
+
+	for _, ft := range formatters {
+		b.Run(ft.name+"/Disabled", func(b *testing.B) {
+			log := benchLogger(ft.f)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				log.Debug("request handled", slog.Int("status", 200))
+			}
+		})
+
+		b.Run(ft.name+"/EnabledNoFields", func(b *testing.B) {
+			log := benchLogger(ft.f)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				log.Info("request handled")
+			}
+		})
+
+		b.Run(ft.name+"/EnabledWithFields", func(b *testing.B) {
+			log := benchLogger(ft.f).With(
+				"service", "api",
+				"version", "1.4.2",
+				"region", "eu-west-1",
+				"instance", 12,
+				"debug", false,
+			)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				log.Info("request handled",
+					slog.String("method", "GET"),
+					slog.Int("status", 200),
+					slog.Duration("latency", 1500*time.Microsecond),
+				)
+			}
+		})
+
+		b.Run(ft.name+"/EnabledWithFieldsAndHook", func(b *testing.B) {
+			log := benchLogger(ft.f).With(
+				"service", "api",
+				"version", "1.4.2",
+				"region", "eu-west-1",
+				"instance", 12,
+				"debug", false,
+			)
+			log.AddHook(func(_ context.Context, entry *formatter.Entry) error {
+				entry.Fields["hooked"] = true
+				return nil
+			})
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				log.Info("request handled",
+					slog.String("method", "GET"),
+					slog.Int("status", 200),
+					slog.Duration("latency", 1500*time.Microsecond),
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package sloggergo
+
+import (
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/godeh/sloggergo/formatter"
+)
+
+// CallerFormat selects how the file of an entry's caller is shown.
+type CallerFormat int
+
+const (
+	// CallerShort shows the file name: server.go:42.
+	CallerShort CallerFormat = iota
+
+	// CallerModule shows the file's path within the main module:
+	// internal/api/server.go:42. Files of other modules show their package
+	// path (github.com/org/lib/client.go:10), and files of package main
+	// their directory (api/main.go:12).
+	CallerModule
+
+	// CallerFull shows the absolute path of the file.
+	CallerFull
+)
+
+// callerOptions controls how callers are found and formatted.
+type callerOptions struct {
+	skip     int
+	format   CallerFormat
+	function bool
+}
+
+// AddCallerSkip skips n more stack frames when finding the caller, for
+// loggers that are always called through a wrapper function. It adds to
+// any skip already set.
+func AddCallerSkip(n int) Option {
+	return func(l *Logger) {
+		l.callerOpts.skip += n
+	}
+}
+
+// WithCallerFormat sets how the caller's file is shown. The default is
+// CallerShort.
+func WithCallerFormat(format CallerFormat) Option {
+	return func(l *Logger) {
+		l.callerOpts.format = format
+	}
+}
+
+// WithCallerFunction adds the calling function's name to the caller:
+// server.go:42 (api.(*Server).handle).
+func WithCallerFunction(enabled bool) Option {
+	return func(l *Logger) {
+		l.callerOpts.function = enabled
+	}
+}
+
+// WithCallerSkip returns a child logger that skips n more stack frames when
+// finding the caller. See AddCallerSkip.
+func (l *Logger) WithCallerSkip(n int) *Logger {
+	l.mu.RLock()
+	attrs := l.attrs
+	l.mu.RUnlock()
+
+	child := l.child(attrs, l.groups)
+	child.callerOpts.skip += n
+	return child
+}
+
+// Helper marks the calling function as a logging helper, like
+// testing.T.Helper: entries logged from within it report the helper's
+// caller instead. It applies to l and every logger derived from or
+// sharing a root with it.
+func (l *Logger) Helper() {
+	var pc [1]uintptr
+	if runtime.Callers(2, pc[:]) == 0 {
+		return
+	}
+	frame, _ := runtime.CallersFrames(pc[:]).Next()
+	l.helpers.add(frame.Function)
+}
+
+// helperSet holds the names of functions marked with Helper.
+type helperSet struct {
+	mu    sync.RWMutex
+	names map[string]struct{}
+}
+
+func (h *helperSet) add(name string) {
+	if h.has(name) {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.names == nil {
+		h.names = make(map[string]struct{})
+	}
+	h.names[name] = struct{}{}
+}
+
+func (h *helperSet) has(name string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	_, ok := h.names[name]
+	return ok
+}
+
+// callerFrame returns the frame skip levels up the stack, plus any skip
+// set with AddCallerSkip, passing over functions marked with Helper.
+// skip counts like runtime.Caller from the function calling callerFrame.
+func (l *Logger) callerFrame(skip int) runtime.Frame {
+	var pcs [16]uintptr
+	n := runtime.Callers(skip+1+l.callerOpts.skip, pcs[:])
+	if n == 0 {
+		return runtime.Frame{}
+	}
+
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !more || !l.helpers.has(frame.Function) {
+			return frame
+		}
+	}
+}
+
+// render formats a frame as the entry's Caller string and Source.
+func (o callerOptions) render(frame runtime.Frame) (string, formatter.Frame) {
+	file := o.file(frame.File, frame.Function)
+	caller := file + ":" + strconv.Itoa(frame.Line)
+	if o.function && frame.Function != "" {
+		caller += " (" + o.functionName(frame.Function) + ")"
+	}
+	return caller, formatter.Frame{Function: frame.Function, File: file, Line: frame.Line}
+}
+
+func (o callerOptions) file(file, function string) string {
+	switch o.format {
+	case CallerFull:
+		return file
+	case CallerModule:
+		return moduleFile(file, function)
+	default:
+		return lastPathElems(file, 1)
+	}
+}
+
+// functionName trims the package path from a function name, leaving the
+// package name (api.(*Server).handle), except with CallerFull.
+func (o callerOptions) functionName(function string) string {
+	if o.format == CallerFull {
+		return function
+	}
+	return function[strings.LastIndexByte(function, '/')+1:]
+}
+
+// mainModule is the path of the main module, if the binary records it.
+var mainModule = sync.OnceValue(func() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		return info.Main.Path
+	}
+	return ""
+})
+
+// moduleFile returns the path of file within its module, using the
+// package path in the function name. See CallerModule.
+func moduleFile(file, function string) string {
+	pkg := packagePath(function)
+	if pkg == "" || pkg == "main" {
+		return lastPathElems(file, 2)
+	}
+
+	base := lastPathElems(file, 1)
+	if mod := mainModule(); mod != "" && (pkg == mod || strings.HasPrefix(pkg, mod+"/")) {
+		if dir := strings.TrimPrefix(pkg[len(mod):], "/"); dir != "" {
+			return dir + "/" + base
+		}
+		return base
+	}
+	return pkg + "/" + base
+}
+
+// packagePath returns the import path of the package defining a function,
+// such as github.com/org/app/api for github.com/org/app/api.(*Server).handle.
+func packagePath(function string) string {
+	slash := strings.LastIndexByte(function, '/')
+	dot := strings.IndexByte(function[slash+1:], '.')
+	if dot < 0 {
+		return ""
+	}
+	return function[:slash+1+dot]
+}
+
+// lastPathElems returns the last n elements of a slash-separated path.
+func lastPathElems(path string, n int) string {
+	for i := len(path) - 1; i > 0; i-- {
+		if path[i] == '/' {
+			n--
+			if n == 0 {
+				return path[i+1:]
+			}
+		}
+	}
+	return path
+}
@@ -0,0 +1,118 @@
+package sloggergo
+
+import (
+	"bytes"
+	"encoding/json"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/godeh/sloggergo/formatter"
+	"github.com/godeh/sloggergo/sink"
+)
+
+// line returns the line number of its caller.
+func line() int {
+	_, _, n, _ := runtime.Caller(1)
+	return n
+}
+
+func logViaHelper(l *Logger, msg string) {
+	l.Helper()
+	l.Info(msg)
+}
+
+func logViaWrapper(l *Logger, msg string) {
+	l.Info(msg)
+}
+
+func TestCallerHelper(t *testing.T) {
+	mock := &mockSink{}
+	log := New(WithSink(mock))
+
+	want := line() + 1
+	logViaHelper(log, "helper")
+
+	if got := mock.entries[0].Caller; got != "caller_test.go:"+strconv.Itoa(want) {
+		t.Errorf("expected the helper's caller, got %s", got)
+	}
+}
+
+func TestCallerSkip(t *testing.T) {
+	mock := &mockSink{}
+	log := New(WithSink(mock), AddCallerSkip(1))
+
+	want := line() + 1
+	logViaWrapper(log, "wrapped")
+	logViaWrapper(log.WithCallerSkip(-1), "unwrapped")
+
+	if got := mock.entries[0].Caller; got != "caller_test.go:"+strconv.Itoa(want) {
+		t.Errorf("expected the wrapper's caller, got %s", got)
+	}
+	if got := mock.entries[1].Source.Function; !strings.HasSuffix(got, ".logViaWrapper") {
+		t.Errorf("expected the wrapper itself, got %s", got)
+	}
+}
+
+func TestSampledLoggerCaller(t *testing.T) {
+	mock := &mockSink{}
+	log := NewSampled(New(WithSink(mock)), &SamplingConfig{Initial: 1})
+
+	want := line() + 1
+	log.Info("sampled")
+	wantf := line() + 1
+	log.Errorf("formatted %d", 1)
+
+	if got := mock.entries[0].Caller; got != "caller_test.go:"+strconv.Itoa(want) {
+		t.Errorf("expected this file as caller, got %s", got)
+	}
+	if got := mock.entries[1].Caller; got != "caller_test.go:"+strconv.Itoa(wantf) {
+		t.Errorf("expected this file as caller, got %s", got)
+	}
+}
+
+func TestCallerFormat(t *testing.T) {
+	mock := &mockSink{}
+	New(WithSink(mock), WithCallerFormat(CallerFull), WithCallerFunction(true)).Info("full")
+	New(WithSink(mock), WithCallerFunction(true)).Info("short")
+
+	full := mock.entries[0].Caller
+	if !strings.HasPrefix(full, "/") || !strings.HasSuffix(full, " (github.com/godeh/sloggergo.TestCallerFormat)") {
+		t.Errorf("unexpected full caller %s", full)
+	}
+	short := mock.entries[1].Caller
+	if !strings.HasPrefix(short, "caller_test.go:") || !strings.HasSuffix(short, " (sloggergo.TestCallerFormat)") {
+		t.Errorf("unexpected short caller %s", short)
+	}
+
+	tests := []struct {
+		file, function, want string
+	}{
+		{"/src/lib/client.go", "github.com/other/lib.(*Client).Do", "github.com/other/lib/client.go"},
+		{"/src/app/cmd/api/main.go", "main.main", "api/main.go"},
+	}
+	for _, tt := range tests {
+		if got := moduleFile(tt.file, tt.function); got != tt.want {
+			t.Errorf("moduleFile(%s, %s) = %s, want %s", tt.file, tt.function, got, tt.want)
+		}
+	}
+}
+
+func TestJSONCaller(t *testing.T) {
+	var buf bytes.Buffer
+	log := New(WithSink(sink.NewStdout(sink.WithWriter(&buf), sink.WithFormatter(formatter.NewJSON()))))
+
+	want := line() + 1
+	log.Info("structured")
+
+	var out struct {
+		Caller formatter.Frame `json:"caller"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Caller.File != "caller_test.go" || out.Caller.Line != want || !strings.HasSuffix(out.Caller.Function, ".TestJSONCaller") {
+		t.Errorf("unexpected caller %+v", out.Caller)
+	}
+}
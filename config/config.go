@@ -4,6 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"regexp"
+
+	"github.com/godeh/sloggergo/level"
+	"github.com/godeh/sloggergo/redact"
 )
 
 // Config represents the complete logger configuration.
@@ -13,18 +17,36 @@ type Config struct {
 
 // LoggerConfig contains all logger settings.
 type LoggerConfig struct {
-	// Level is the minimum log level (debug, info, warn, error, fatal)
+	// Level is the minimum log level (trace, debug, info, notice, warn,
+	// error, critical, fatal or a registered custom level)
 	Level string `json:"level"`
 
+	// Name is the name of the root logger, added as the "logger" field
+	Name string `json:"name"`
+
+	// Levels sets the minimum level per logger name prefix, for example
+	// {"db": "debug", "http": "warn"}
+	Levels map[string]string `json:"levels"`
+
 	// Format is the output format (json, text)
 	Format string `json:"format"`
 
-	// TimeFormat is the time format for logs (default: RFC3339Nano)
+	// TimeFormat is the time layout for logs, or unix, unixmilli or
+	// unixnano for Unix timestamps (default: RFC3339Nano)
 	TimeFormat string `json:"time_format"`
 
 	// AddCaller enables caller information
 	AddCaller bool `json:"add_caller"`
 
+	// Stacktrace configuration
+	Stacktrace StacktraceConfig `json:"stacktrace"`
+
+	// Limits bounds the size of entries
+	Limits LimitsConfig `json:"limits"`
+
+	// Redact configures redaction of sensitive fields
+	Redact RedactConfig `json:"redact"`
+
 	// Stdout configuration
 	Stdout StdoutConfig `json:"stdout"`
 
@@ -32,10 +54,57 @@ type LoggerConfig struct {
 	File FileConfig `json:"file"`
 }
 
+// StacktraceConfig configures stack traces on log entries.
+type StacktraceConfig struct {
+	// Level is the minimum level that gets a stack trace (default: none)
+	Level string `json:"level"`
+
+	// Depth is the maximum number of frames (default: 32)
+	Depth int `json:"depth"`
+}
+
+// LimitsConfig bounds the size of entries. Zero values are not enforced.
+type LimitsConfig struct {
+	MaxMessageLength int `json:"max_message_length"`
+	MaxStringLength  int `json:"max_string_length"`
+	MaxFields        int `json:"max_fields"`
+	MaxDepth         int `json:"max_depth"`
+	MaxEntrySize     int `json:"max_entry_size"`
+}
+
+// RedactConfig configures redaction of sensitive fields.
+type RedactConfig struct {
+	// Strategy is the default redaction: mask, hash or partial (default: mask)
+	Strategy string `json:"strategy"`
+
+	// Keys are field names, globs or dotted paths to redact, such as
+	// "*password*" or "auth.token"
+	Keys []string `json:"keys"`
+
+	// Rules are redaction rules with their own strategy
+	Rules []RedactRule `json:"rules"`
+}
+
+// RedactRule redacts the fields matching Key, or the parts of string values
+// matching the regular expression Pattern. Exactly one of them is set.
+type RedactRule struct {
+	Key      string `json:"key"`
+	Pattern  string `json:"pattern"`
+	Strategy string `json:"strategy"`
+}
+
+// Enabled reports whether any redaction is configured.
+func (c RedactConfig) Enabled() bool {
+	return len(c.Keys) > 0 || len(c.Rules) > 0
+}
+
 // StdoutConfig configures stdout output.
 type StdoutConfig struct {
 	Enabled       bool `json:"enabled"`
 	DisableColors bool `json:"disable_colors"`
+
+	// Level is the minimum level written to stdout (default: all entries)
+	Level string `json:"level"`
 }
 
 // FileConfig configures file output.
@@ -44,6 +113,9 @@ type FileConfig struct {
 	Path       string `json:"path"`
 	MaxSizeMB  int    `json:"max_size_mb"`
 	MaxBackups int    `json:"max_backups"`
+
+	// Level is the minimum level written to the file (default: all entries)
+	Level string `json:"level"`
 }
 
 // Load reads and parses a configuration file.
@@ -72,9 +144,14 @@ func Load(path string) (*Config, error) {
 // Validate checks the configuration for errors.
 func (c *Config) Validate() error {
 	// Validate level
-	level := c.Logger.Level
-	if level != "debug" && level != "info" && level != "warn" && level != "error" && level != "fatal" {
-		return fmt.Errorf("invalid log level: %s", level)
+	if _, err := level.Parse(c.Logger.Level); err != nil {
+		return err
+	}
+
+	for name, lvl := range c.Logger.Levels {
+		if _, err := level.Parse(lvl); err != nil {
+			return fmt.Errorf("logger %s: %w", name, err)
+		}
 	}
 
 	// Validate format
@@ -83,6 +160,28 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid format: %s", format)
 	}
 
+	// Validate sink and stack trace levels
+	for name, lvl := range map[string]string{
+		"stdout":     c.Logger.Stdout.Level,
+		"file":       c.Logger.File.Level,
+		"stacktrace": c.Logger.Stacktrace.Level,
+	} {
+		if lvl == "" {
+			continue
+		}
+		if _, err := level.Parse(lvl); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	if err := c.Logger.Limits.validate(); err != nil {
+		return fmt.Errorf("limits: %w", err)
+	}
+
+	if err := c.Logger.Redact.validate(); err != nil {
+		return fmt.Errorf("redact: %w", err)
+	}
+
 	// Validate file path if enabled
 	if c.Logger.File.Enabled && c.Logger.File.Path == "" {
 		return fmt.Errorf("file path is required when file output is enabled")
@@ -90,3 +189,40 @@ func (c *Config) Validate() error {
 
 	return nil
 }
+
+func (c RedactConfig) validate() error {
+	if _, err := redact.ParseStrategy(c.Strategy); err != nil {
+		return err
+	}
+	for i, rule := range c.Rules {
+		if (rule.Key == "") == (rule.Pattern == "") {
+			return fmt.Errorf("rule %d: exactly one of key and pattern is required", i)
+		}
+		if rule.Pattern != "" {
+			if _, err := regexp.Compile(rule.Pattern); err != nil {
+				return fmt.Errorf("rule %d: %w", i, err)
+			}
+		}
+		if rule.Strategy != "" {
+			if _, err := redact.ParseStrategy(rule.Strategy); err != nil {
+				return fmt.Errorf("rule %d: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (c LimitsConfig) validate() error {
+	for name, v := range map[string]int{
+		"max_message_length": c.MaxMessageLength,
+		"max_string_length":  c.MaxStringLength,
+		"max_fields":         c.MaxFields,
+		"max_depth":          c.MaxDepth,
+		"max_entry_size":     c.MaxEntrySize,
+	} {
+		if v < 0 {
+			return fmt.Errorf("%s must not be negative", name)
+		}
+	}
+	return nil
+}
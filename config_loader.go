@@ -1,8 +1,11 @@
 package sloggergo
 
 import (
+	"regexp"
+
 	"github.com/godeh/sloggergo/config"
 	"github.com/godeh/sloggergo/formatter"
+	"github.com/godeh/sloggergo/redact"
 	"github.com/godeh/sloggergo/sink"
 )
 
@@ -22,12 +25,44 @@ func NewFromConfig(path string) (*Logger, error) {
 
 // NewFromConfigStruct creates a new logger from a config struct.
 func NewFromConfigStruct(cfg *config.Config) (*Logger, error) {
-	level := ParseLevel(cfg.Logger.Level)
-	logger := New(
+	level, err := ParseLevel(cfg.Logger.Level)
+	if err != nil {
+		return nil, err
+	}
+	opts := []Option{
 		WithLevel(level),
 		WithCaller(cfg.Logger.AddCaller),
 		WithTimeFormat(cfg.Logger.TimeFormat),
-	)
+	}
+	if cfg.Logger.Name != "" {
+		opts = append(opts, WithName(cfg.Logger.Name))
+	}
+	if cfg.Logger.Stacktrace.Level != "" {
+		stackLevel, err := ParseLevel(cfg.Logger.Stacktrace.Level)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithStacktrace(stackLevel))
+	}
+	if cfg.Logger.Stacktrace.Depth > 0 {
+		opts = append(opts, WithStacktraceDepth(cfg.Logger.Stacktrace.Depth))
+	}
+	for name, lvl := range cfg.Logger.Levels {
+		nameLevel, err := ParseLevel(lvl)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithNamedLevel(name, nameLevel))
+	}
+	opts = append(opts, WithLimits(Limits(cfg.Logger.Limits)))
+	if cfg.Logger.Redact.Enabled() {
+		r, err := newRedactor(cfg.Logger.Redact)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithHook(r.Hook, HookName("redact")))
+	}
+	logger := New(opts...)
 
 	var fmt formatter.Formatter
 	if cfg.Logger.Format == "json" {
@@ -37,14 +72,19 @@ func NewFromConfigStruct(cfg *config.Config) (*Logger, error) {
 	}
 
 	if cfg.Logger.Stdout.Enabled {
-		var textFmt *formatter.TextFormatter
+		var stdoutSink sink.Sink
 		if cfg.Logger.Format == "text" {
-			textFmt = formatter.NewText()
+			textFmt := formatter.NewText()
 			textFmt.DisableColors = cfg.Logger.Stdout.DisableColors
-			logger.AddSink(sink.NewStdout(sink.WithFormatter(textFmt)))
+			stdoutSink = sink.NewStdout(sink.WithFormatter(textFmt))
 		} else {
-			logger.AddSink(sink.NewStdout(sink.WithFormatter(fmt)))
+			stdoutSink = sink.NewStdout(sink.WithFormatter(fmt))
+		}
+		stdoutSink, err = filterSink(stdoutSink, cfg.Logger.Stdout.Level)
+		if err != nil {
+			return nil, err
 		}
+		logger.AddSink(stdoutSink)
 	}
 
 	if cfg.Logger.File.Enabled {
@@ -52,8 +92,55 @@ func NewFromConfigStruct(cfg *config.Config) (*Logger, error) {
 		if err != nil {
 			return nil, err
 		}
-		logger.AddSink(fileSink)
+		filtered, err := filterSink(fileSink, cfg.Logger.File.Level)
+		if err != nil {
+			fileSink.Close()
+			return nil, err
+		}
+		logger.AddSink(filtered)
 	}
 
 	return logger, nil
 }
+
+// filterSink wraps s with a minimum level, if one is configured.
+func filterSink(s sink.Sink, lvl string) (sink.Sink, error) {
+	if lvl == "" {
+		return s, nil
+	}
+	minLevel, err := ParseLevel(lvl)
+	if err != nil {
+		return nil, err
+	}
+	return sink.NewFilter(s, sink.WithMinLevel(minLevel)), nil
+}
+
+// newRedactor builds a redactor from the redact section of a config.
+func newRedactor(cfg config.RedactConfig) (*redact.Redactor, error) {
+	strategy, err := redact.ParseStrategy(cfg.Strategy)
+	if err != nil {
+		return nil, err
+	}
+	opts := []redact.Option{
+		redact.WithStrategy(strategy),
+		redact.WithKeys(cfg.Keys...),
+	}
+	for _, rule := range cfg.Rules {
+		var ruleStrategy redact.Strategy
+		if rule.Strategy != "" {
+			if ruleStrategy, err = redact.ParseStrategy(rule.Strategy); err != nil {
+				return nil, err
+			}
+		}
+		if rule.Key != "" {
+			opts = append(opts, redact.WithKeyStrategy(rule.Key, ruleStrategy))
+			continue
+		}
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, redact.WithValuePattern(re, ruleStrategy))
+	}
+	return redact.New(opts...), nil
+}
@@ -0,0 +1,72 @@
+package sloggergo
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync/atomic"
+
+	"github.com/godeh/sloggergo/sink"
+)
+
+type loggerKey struct{}
+
+type attrsKey struct{}
+
+var defaultLogger atomic.Pointer[Logger]
+
+// Default returns the logger used by FromContext when the context holds
+// none. Unless replaced with SetDefault, it writes INFO and above to stdout.
+func Default() *Logger {
+	if l := defaultLogger.Load(); l != nil {
+		return l
+	}
+	defaultLogger.CompareAndSwap(nil, New(WithSink(sink.NewStdout())))
+	return defaultLogger.Load()
+}
+
+// SetDefault replaces the logger returned by Default.
+func SetDefault(l *Logger) {
+	defaultLogger.Store(l)
+}
+
+// NewContext returns a copy of ctx that carries l.
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext returns the logger stored in ctx by NewContext,
+// or Default if there is none.
+func FromContext(ctx context.Context) *Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(loggerKey{}).(*Logger); ok && l != nil {
+			return l
+		}
+	}
+	return Default()
+}
+
+// ContextWith returns a copy of ctx carrying attrs in addition to any
+// attributes already attached to it. Every logger adds them to entries
+// logged with that context, after its own fields and before those of the
+// call, without needing a ContextExtractor.
+func ContextWith(ctx context.Context, attrs ...slog.Attr) context.Context {
+	if len(attrs) == 0 {
+		return ctx
+	}
+	merged := slices.Clone(ContextAttrs(ctx))
+	for _, a := range attrs {
+		merged = appendAttr(merged, a)
+	}
+	return context.WithValue(ctx, attrsKey{}, merged)
+}
+
+// ContextAttrs returns the attributes attached to ctx with ContextWith.
+// The returned slice must not be modified.
+func ContextAttrs(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	attrs, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	return attrs
+}
@@ -0,0 +1,51 @@
+package sloggergo
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+)
+
+func TestLoggerContext(t *testing.T) {
+	if FromContext(context.Background()) != Default() {
+		t.Error("expected the default logger for an empty context")
+	}
+
+	mock := &mockSink{}
+	log := New(WithSink(mock)).With("request_id", "abc")
+	ctx := NewContext(context.Background(), log)
+
+	FromContext(ctx).Info("handled")
+
+	if mock.Len() != 1 || mock.entries[0].Fields["request_id"] != "abc" {
+		t.Errorf("expected the request logger to be used, got %d entries", mock.Len())
+	}
+}
+
+func TestContextWith(t *testing.T) {
+	mock := &mockSink{}
+	log := New(WithSink(mock), WithFields(map[string]any{"service": "api"}))
+
+	parent := ContextWith(context.Background(), slog.String("user", "alice"), slog.Int("tenant", 1))
+	child := ContextWith(parent, slog.Int("tenant", 2))
+
+	log.InfoContext(child, "child", slog.String("user", "bob"))
+	log.InfoContext(parent, "parent")
+
+	got := mock.entries[0].Attrs
+	want := []string{"service", "user", "tenant"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d fields, got %v", len(want), got)
+	}
+	for i, key := range want {
+		if got[i].Key != key {
+			t.Errorf("field %d: expected %s, got %s", i, key, got[i].Key)
+		}
+	}
+	if mock.entries[0].Fields["user"] != "bob" || mock.entries[0].Fields["tenant"] != int64(2) {
+		t.Errorf("unexpected child fields: %v", mock.entries[0].Fields)
+	}
+	if mock.entries[1].Fields["tenant"] != int64(1) {
+		t.Errorf("expected the parent context to be unchanged, got %v", mock.entries[1].Fields)
+	}
+}
@@ -0,0 +1,13 @@
+package sloggergo
+
+import "log/slog"
+
+// ErrorKey is the field name used by Err.
+const ErrorKey = "error"
+
+// Err returns an attribute holding err under the "error" key.
+// Formatters render it with the error's message, concrete type, wrapped
+// errors and any stack trace it carries.
+func Err(err error) slog.Attr {
+	return slog.Any(ErrorKey, err)
+}
@@ -0,0 +1,70 @@
+package sloggergo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/godeh/sloggergo/formatter"
+	"github.com/godeh/sloggergo/sink"
+)
+
+// stackError carries the stack where it was created.
+type stackError struct {
+	msg string
+	pcs []uintptr
+}
+
+func newStackError(msg string) *stackError {
+	pcs := make([]uintptr, 8)
+	n := runtime.Callers(2, pcs)
+	return &stackError{msg: msg, pcs: pcs[:n]}
+}
+
+func (e *stackError) Error() string      { return e.msg }
+func (e *stackError) Callers() []uintptr { return e.pcs }
+
+func TestErrJSON(t *testing.T) {
+	var buf bytes.Buffer
+	log := New(WithSink(sink.NewStdout(sink.WithWriter(&buf), sink.WithFormatter(formatter.NewJSON()))), WithCaller(false))
+
+	base := newStackError("disk full")
+	err := fmt.Errorf("save: %w", errors.Join(base, fs.ErrClosed))
+	log.Error("failed", Err(err))
+
+	var out struct {
+		Fields struct {
+			Error formatter.ErrorInfo `json:"error"`
+		} `json:"fields"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	got := out.Fields.Error
+	if got.Message != err.Error() || got.Type != "*fmt.wrapError" {
+		t.Errorf("unexpected message or type: %+v", got)
+	}
+	if len(got.Chain) != 3 || got.Chain[1].Message != "disk full" || got.Chain[2].Message != fs.ErrClosed.Error() {
+		t.Errorf("unexpected chain: %+v", got.Chain)
+	}
+	if len(got.Stack) == 0 || !strings.HasSuffix(got.Stack[0].Function, ".TestErrJSON") {
+		t.Errorf("expected the stack of the wrapped error, got %+v", got.Stack)
+	}
+}
+
+func TestErrText(t *testing.T) {
+	var buf bytes.Buffer
+	log := New(WithSink(sink.NewStdout(sink.WithWriter(&buf), sink.WithFormatter(&formatter.TextFormatter{DisableColors: true, DisableTimestamp: true}))), WithCaller(false))
+
+	log.Error("failed", Err(fmt.Errorf("open config: %w", fs.ErrNotExist)))
+
+	want := "ERROR failed error=open config: file does not exist error.type=*fmt.wrapError error.chain=[*errors.errorString: file does not exist]\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
@@ -2,13 +2,13 @@ package main
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"log/slog"
 	"strings"
 
 	"github.com/godeh/sloggergo"
 	"github.com/godeh/sloggergo/formatter"
+	"github.com/godeh/sloggergo/redact"
 	"github.com/godeh/sloggergo/sink"
 )
 
@@ -17,23 +17,10 @@ type contextKey string
 
 const traceIDKey contextKey = "trace_id"
 
-// Sanitization Hook (PII Masking)
-func piiSanitizerHook(ctx context.Context, entry *formatter.Entry) error {
-	if email, ok := entry.Fields["email"]; ok {
-		if emailStr, ok := email.(string); ok {
-			parts := strings.Split(emailStr, "@")
-			if len(parts) == 2 {
-				entry.Fields["email"] = parts[0][:1] + "***@" + parts[1]
-			}
-		}
-	}
-	return nil
-}
-
 // Error Dropping Hook (Drop logs containing "ignore_me")
 func errorDropperHook(ctx context.Context, entry *formatter.Entry) error {
 	if strings.Contains(entry.Message, "ignore_me") {
-		return errors.New("log dropped by hook")
+		return sloggergo.ErrDropEntry
 	}
 	return nil
 }
@@ -54,12 +41,18 @@ func main() {
 
 	stdoutSink := sink.NewStdout(sink.WithFormatter(prettyFormatter))
 
+	// Redaction (PII Masking)
+	redactor := redact.New(
+		redact.WithKeys("*password*"),
+		redact.WithKeyStrategy("email", redact.Partial(12)),
+	)
+
 	// 2. Initialize Logger with Features
 	log := sloggergo.New(
 		sloggergo.WithSink(stdoutSink),
 		sloggergo.WithLevel(sloggergo.InfoLevel),
 		sloggergo.WithContextExtractor(traceExtractor),
-		sloggergo.WithHook(piiSanitizerHook),
+		sloggergo.WithHook(redactor.Hook, sloggergo.HookName("redact")),
 		sloggergo.WithHook(errorDropperHook),
 	)
 	defer log.Close()
@@ -81,7 +74,7 @@ func main() {
 
 	fmt.Println("\n=== 3. Hooks: PII Sanitization ===")
 	// Should mask email
-	log.Info("User login", slog.String("email", "john.doe@example.com"))
+	log.Info("User login", slog.String("email", "john.doe@example.com"), slog.String("password", "hunter2"))
 
 	fmt.Println("\n=== 4. Hooks: Dropping Logs ===")
 	// Should NOT be printed
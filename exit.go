@@ -0,0 +1,71 @@
+package sloggergo
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/godeh/sloggergo/sink"
+)
+
+// exitState holds what Fatal does after writing its entry.
+// It is shared by a logger and every logger derived from it.
+type exitState struct {
+	mu       sync.Mutex
+	fn       func(code int)
+	handlers []func()
+}
+
+// RegisterExitHandler adds a function that Fatal runs before the sinks
+// are closed and the process exits, such as flushing metrics or closing
+// database connections. Handlers run in the order they were registered
+// and are shared with loggers derived from this one.
+func (l *Logger) RegisterExitHandler(fn func()) {
+	l.exit.mu.Lock()
+	defer l.exit.mu.Unlock()
+	l.exit.handlers = append(l.exit.handlers, fn)
+}
+
+// Sync flushes every sink that buffers output.
+func (l *Logger) Sync() error {
+	var errs []error
+	for _, s := range l.loadSinks() {
+		if syncer, ok := s.(sink.Syncer); ok {
+			if err := syncer.Sync(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// fatalExit runs the exit handlers, syncs and closes every sink and then
+// calls the exit function. A panicking handler does not stop the others.
+func (l *Logger) fatalExit(code int) {
+	l.exit.mu.Lock()
+	handlers := l.exit.handlers
+	exit := l.exit.fn
+	l.exit.mu.Unlock()
+
+	for _, fn := range handlers {
+		l.runExitHandler(fn)
+	}
+
+	if err := l.Sync(); err != nil {
+		l.handleError(err)
+	}
+	if err := l.Close(); err != nil {
+		l.handleError(err)
+	}
+
+	exit(code)
+}
+
+func (l *Logger) runExitHandler(fn func()) {
+	defer func() {
+		if r := recover(); r != nil && l.errorHandler != nil {
+			l.errorHandler(fmt.Errorf("exit handler panicked: %v", r))
+		}
+	}()
+	fn()
+}
@@ -0,0 +1,95 @@
+package sloggergo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/godeh/sloggergo/formatter"
+)
+
+type closingSink struct {
+	mockSink
+	synced, closed bool
+}
+
+func (c *closingSink) Sync() error {
+	c.synced = true
+	return nil
+}
+
+func (c *closingSink) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestFatalExit(t *testing.T) {
+	s := &closingSink{}
+	code := -1
+	log := New(WithSink(s), WithExitFunc(func(c int) { code = c }))
+
+	var order []string
+	log.RegisterExitHandler(func() { order = append(order, "first") })
+	log.With("request_id", "r-1").RegisterExitHandler(func() { order = append(order, "second") })
+
+	log.Fatal("cannot continue")
+
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+	if s.Len() != 1 || s.entries[0].Level != "FATAL" {
+		t.Errorf("expected one FATAL entry, got %d", s.Len())
+	}
+	if !s.synced || !s.closed {
+		t.Errorf("expected sink to be synced and closed, synced=%v closed=%v", s.synced, s.closed)
+	}
+	if len(order) != 2 || order[0] != "first" || order[1] != "second" {
+		t.Errorf("expected exit handlers to run in order, got %v", order)
+	}
+}
+
+// slowSink is a test sink that takes a while to write entries below FATAL.
+type slowSink struct {
+	closingSink
+}
+
+func (s *slowSink) Write(entry *formatter.Entry) error {
+	if entry.Level != "FATAL" {
+		time.Sleep(50 * time.Millisecond)
+	}
+	if s.closed {
+		return nil
+	}
+	return s.mockSink.Write(entry)
+}
+
+func TestAsyncFatalWaitsForWrites(t *testing.T) {
+	s := &slowSink{}
+	log := New(WithSink(s), WithExitFunc(func(int) {}))
+	async := NewAsync(log, WithWorkers(1))
+
+	async.Info("in flight")
+	time.Sleep(10 * time.Millisecond) // let the worker take it
+	async.Fatal("cannot continue")
+
+	if s.Len() != 2 || s.entries[0].Message != "in flight" {
+		t.Errorf("expected the in-flight entry to be written before the sinks closed, got %d entries", s.Len())
+	}
+}
+
+func TestPanic(t *testing.T) {
+	mock := &mockSink{}
+	log := New(WithSink(mock))
+
+	defer func() {
+		r := recover()
+		if r != "bad state" {
+			t.Errorf("expected panic with message, got %v", r)
+		}
+		if mock.Len() != 1 || mock.entries[0].Level != "PANIC" {
+			t.Errorf("expected one PANIC entry before panicking")
+		}
+	}()
+
+	log.Panic("bad state")
+	t.Error("expected Panic to panic")
+}
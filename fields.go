@@ -0,0 +1,245 @@
+package sloggergo
+
+import (
+	"log/slog"
+	"reflect"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/godeh/sloggergo/formatter"
+)
+
+// maxResolveDepth bounds how deeply groups are resolved, guarding against
+// LogValuers that keep returning groups containing themselves.
+const maxResolveDepth = 16
+
+// appendAttr adds a to attrs with last-write-wins semantics: a key that is
+// already present keeps its position and takes the new value, and groups
+// with the same key are merged. LogValuers are resolved first, including
+// those inside groups. Empty groups are dropped and groups with an empty
+// key are inlined, as in log/slog.
+//
+// attrs may be modified in place, so callers must own it; group values
+// are never modified since they may be shared with a parent logger.
+func appendAttr(attrs []slog.Attr, a slog.Attr) []slog.Attr {
+	return appendAttrDepth(attrs, a, 0)
+}
+
+func appendAttrDepth(attrs []slog.Attr, a slog.Attr, depth int) []slog.Attr {
+	a.Value = a.Value.Resolve()
+
+	if a.Value.Kind() == slog.KindGroup {
+		group := a.Value.Group()
+		if len(group) == 0 {
+			return attrs
+		}
+
+		switch {
+		case a.Key == "":
+			for _, ga := range group {
+				attrs = appendAttrDepth(attrs, ga, depth+1)
+			}
+			return attrs
+		case depth >= maxResolveDepth:
+			a.Value = slog.StringValue("!MAXDEPTH")
+		default:
+			var merged []slog.Attr
+			if i := indexAttr(attrs, a.Key); i >= 0 && attrs[i].Value.Kind() == slog.KindGroup {
+				merged = slices.Clone(attrs[i].Value.Group())
+			}
+			for _, ga := range group {
+				merged = appendAttrDepth(merged, ga, depth+1)
+			}
+			a.Value = slog.GroupValue(merged...)
+		}
+	}
+
+	if i := indexAttr(attrs, a.Key); i >= 0 {
+		attrs[i] = a
+		return attrs
+	}
+	return append(attrs, a)
+}
+
+// addEntryAttr adds a to the entry's attributes like appendAttr. If a
+// replaces or merges into one of the logger fields covered by the entry's
+// cache, the cache no longer matches and is dropped.
+func addEntryAttr(entry *formatter.Entry, a slog.Attr) {
+	if c := entry.Cache; c != nil && (a.Key == "" || indexAttr(entry.Attrs[:c.Len()], a.Key) >= 0) {
+		entry.Cache = nil
+	}
+	entry.Attrs = appendAttr(entry.Attrs, a)
+}
+
+// fillFields fills in entry.Fields from entry.Attrs, taking the logger's
+// fields from the cache while it still applies.
+func fillFields(entry *formatter.Entry) {
+	if entry.Fields == nil {
+		entry.Fields = make(map[string]any, len(entry.Attrs))
+	}
+	clear(entry.Fields)
+	for i, a := range entry.Attrs {
+		if entry.Cache != nil && i < entry.Cache.Len() {
+			entry.Fields[a.Key] = entry.Cache.FieldValue(i)
+		} else {
+			entry.Fields[a.Key] = formatter.FieldValue(a.Value)
+		}
+	}
+}
+
+// newAttrCache returns the cache for a logger's fields, or nil if it has none.
+func newAttrCache(attrs []slog.Attr) *formatter.AttrCache {
+	if len(attrs) == 0 {
+		return nil
+	}
+	return formatter.NewAttrCache(attrs)
+}
+
+// maxPooledAttrs is the largest attribute slice kept in a pooled entry.
+const maxPooledAttrs = 256
+
+var entryPool = sync.Pool{
+	New: func() any {
+		return &formatter.Entry{Fields: make(map[string]any)}
+	},
+}
+
+// releaseEntry returns an entry built by newEntry to the pool.
+func releaseEntry(entry *formatter.Entry) {
+	attrs, fields := entry.Attrs, entry.Fields
+	if cap(attrs) > maxPooledAttrs {
+		return
+	}
+	clear(attrs)
+	clear(fields)
+	if fields == nil {
+		fields = make(map[string]any)
+	}
+	*entry = formatter.Entry{Attrs: attrs[:0], Fields: fields}
+	entryPool.Put(entry)
+}
+
+func indexAttr(attrs []slog.Attr, key string) int {
+	for i := range attrs {
+		if attrs[i].Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// cloneFields copies a field map, including nested groups, so that changes
+// made by a hook can be detected afterwards.
+func cloneFields(fields map[string]any) map[string]any {
+	out := make(map[string]any, len(fields))
+	for k, v := range fields {
+		if g, ok := v.(formatter.Group); ok {
+			v = formatter.Group(cloneFields(g))
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// syncFields copies the changes made to entry.Fields since before was
+// taken into entry.Attrs, then rebuilds entry.Fields from the result.
+func syncFields(entry *formatter.Entry, before map[string]any) {
+	entry.Attrs = syncAttrs(entry.Attrs, entry.Fields, before)
+	entry.Fields = formatter.FieldMap(entry.Attrs)
+}
+
+// syncAttrs applies the changes from before to fields onto attrs. Changed
+// keys keep their position, removed keys are dropped and new keys are
+// appended in sorted order. Groups that were changed are synced the same
+// way, so their own order survives too.
+func syncAttrs(attrs []slog.Attr, fields, before map[string]any) []slog.Attr {
+	out := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		v, ok := fields[a.Key]
+		old, had := before[a.Key]
+		switch {
+		case had && !ok:
+			continue
+		case had && !sameField(v, old):
+			g, isGroup := v.(formatter.Group)
+			oldGroup, wasGroup := old.(formatter.Group)
+			if isGroup && wasGroup && a.Value.Kind() == slog.KindGroup {
+				a.Value = slog.GroupValue(syncAttrs(a.Value.Group(), g, oldGroup)...)
+			} else {
+				a = formatter.FieldAttr(a.Key, v)
+			}
+		}
+		out = append(out, a)
+	}
+
+	var added []string
+	for k := range fields {
+		if _, had := before[k]; !had && indexAttr(out, k) < 0 {
+			added = append(added, k)
+		}
+	}
+	sort.Strings(added)
+	for _, k := range added {
+		out = append(out, formatter.FieldAttr(k, fields[k]))
+	}
+	return out
+}
+
+// prefixUnchanged reports whether the first n of the attributes a hook saw,
+// old, are still the first n of the entry's attributes with the same
+// values, given the fields from before the hook ran.
+func prefixUnchanged(n int, old []slog.Attr, entry *formatter.Entry, before map[string]any) bool {
+	if len(entry.Attrs) < n {
+		return false
+	}
+	for i, a := range old[:n] {
+		if entry.Attrs[i].Key != a.Key || !sameField(entry.Fields[a.Key], before[a.Key]) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameField reports whether a hook left a field value untouched.
+// Comparable values are compared with ==, groups key by key, maps, slices
+// and funcs by identity, and anything else is reported as changed.
+func sameField(a, b any) bool {
+	ga, okA := a.(formatter.Group)
+	gb, okB := b.(formatter.Group)
+	if okA || okB {
+		if !okA || !okB || len(ga) != len(gb) {
+			return false
+		}
+		for k, v := range ga {
+			bv, ok := gb[k]
+			if !ok || !sameField(v, bv) {
+				return false
+			}
+		}
+		return true
+	}
+
+	ta, tb := reflect.TypeOf(a), reflect.TypeOf(b)
+	if ta != tb {
+		return false
+	}
+	if ta == nil {
+		return true
+	}
+
+	// A comparable type can still hold an uncomparable value, such as a
+	// struct with an interface field set to a slice, so check the values.
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.Comparable() && vb.Comparable() {
+		return a == b
+	}
+	switch va.Kind() {
+	case reflect.Map, reflect.Slice:
+		return va.Pointer() == vb.Pointer() && va.Len() == vb.Len()
+	case reflect.Func:
+		return va.Pointer() == vb.Pointer()
+	default:
+		return false
+	}
+}
@@ -0,0 +1,88 @@
+package formatter
+
+import (
+	"log/slog"
+	"sync"
+)
+
+// AttrCache holds a fixed list of attributes together with their map view
+// and their encoding by each formatter that has written them. Loggers
+// created with With keep one for their fields, so that those fields are
+// encoded once per formatter rather than once per entry. It is safe for
+// concurrent use.
+type AttrCache struct {
+	attrs  []slog.Attr
+	values []any
+
+	mu      sync.RWMutex
+	encoded map[string]cachedEncoding
+}
+
+type cachedEncoding struct {
+	data []byte
+	ok   bool
+}
+
+// NewAttrCache returns a cache for attrs, which must not be modified
+// afterwards.
+func NewAttrCache(attrs []slog.Attr) *AttrCache {
+	c := &AttrCache{attrs: attrs, values: make([]any, len(attrs))}
+	for i, a := range attrs {
+		// Groups are mutable maps in the Fields view, so each entry gets
+		// its own copy of those.
+		if a.Value.Kind() != slog.KindGroup {
+			c.values[i] = FieldValue(a.Value)
+		}
+	}
+	return c
+}
+
+// Len returns the number of cached attributes.
+func (c *AttrCache) Len() int {
+	return len(c.attrs)
+}
+
+// FieldValue returns the map view of the i-th attribute, as FieldValue
+// would compute it.
+func (c *AttrCache) FieldValue(i int) any {
+	if c.attrs[i].Value.Kind() == slog.KindGroup {
+		return FieldValue(c.attrs[i].Value)
+	}
+	return c.values[i]
+}
+
+// encoding returns the encoding of the cached attributes stored under key,
+// calling encode the first time. encode reports false for attributes it
+// cannot encode on their own; the formatter then encodes them per entry.
+func (c *AttrCache) encoding(key string, encode func([]slog.Attr) ([]byte, bool)) ([]byte, bool) {
+	c.mu.RLock()
+	enc, found := c.encoded[key]
+	c.mu.RUnlock()
+	if found {
+		return enc.data, enc.ok
+	}
+
+	data, ok := encode(c.attrs)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.encoded == nil {
+		c.encoded = make(map[string]cachedEncoding)
+	}
+	c.encoded[key] = cachedEncoding{data: data, ok: ok}
+	return data, ok
+}
+
+// cachedPrefix returns the cached encoding of the leading attributes of the
+// entry under key, and the attributes that remain to be encoded.
+func cachedPrefix(entry *Entry, attrs []slog.Attr, key string, encode func([]slog.Attr) ([]byte, bool)) ([]byte, []slog.Attr) {
+	c := entry.Cache
+	if c == nil || c.Len() == 0 || c.Len() > len(attrs) {
+		return nil, attrs
+	}
+	data, ok := c.encoding(key, encode)
+	if !ok {
+		return nil, attrs
+	}
+	return data, attrs[c.Len():]
+}
@@ -0,0 +1,138 @@
+package formatter
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// maxErrorChain limits the number of wrapped errors reported for one error.
+const maxErrorChain = 32
+
+// ErrorInfo is the structured form of an error field. JSONFormatter
+// renders it as an object; TextFormatter as the message followed by
+// .type, .chain and .stack keys.
+type ErrorInfo struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+
+	// Chain lists the wrapped errors, as returned by Unwrap, depth first.
+	// Each branch of an errors.Join is followed in turn.
+	Chain []ErrorInfo `json:"chain,omitempty"`
+
+	// Stack is the stack trace carried by the error, if any.
+	Stack Stack `json:"stack,omitempty"`
+}
+
+// NewErrorInfo describes err. The stack comes from the innermost error in
+// the chain that carries one, through either a Callers() []uintptr method
+// or a StackTrace method returning program counters, as in
+// github.com/pkg/errors.
+func NewErrorInfo(err error) ErrorInfo {
+	info := ErrorInfo{Message: err.Error(), Type: fmt.Sprintf("%T", err)}
+	info.Stack = errorStack(err)
+
+	walkErrors(err, func(e error) bool {
+		if len(info.Chain) == maxErrorChain {
+			return false
+		}
+		info.Chain = append(info.Chain, ErrorInfo{Message: e.Error(), Type: fmt.Sprintf("%T", e)})
+		if st := errorStack(e); st != nil {
+			info.Stack = st
+		}
+		return true
+	})
+	return info
+}
+
+// chainString renders the chain for text output, for example
+// [*fs.PathError: open x: no such file, syscall.Errno: no such file].
+func (info ErrorInfo) chainString() string {
+	parts := make([]string, len(info.Chain))
+	for i, c := range info.Chain {
+		parts[i] = c.Type + ": " + c.Message
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+// errorValue returns the error held by v, if any.
+func errorValue(v slog.Value) (error, bool) {
+	if v.Kind() != slog.KindAny {
+		return nil, false
+	}
+	err, ok := v.Any().(error)
+	return err, ok
+}
+
+// walkErrors calls fn for each error wrapped by err, depth first, until
+// fn returns false.
+func walkErrors(err error, fn func(error) bool) bool {
+	var wrapped []error
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		if u := e.Unwrap(); u != nil {
+			wrapped = []error{u}
+		}
+	case interface{ Unwrap() []error }:
+		wrapped = e.Unwrap()
+	}
+
+	for _, w := range wrapped {
+		if w == nil {
+			continue
+		}
+		if !fn(w) || !walkErrors(w, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// errorStack returns the stack trace carried by err itself, ignoring the
+// errors it wraps.
+func errorStack(err error) Stack {
+	var pcs []uintptr
+	if c, ok := err.(interface{ Callers() []uintptr }); ok {
+		pcs = c.Callers()
+	} else {
+		pcs = stackTracePCs(err)
+	}
+	if len(pcs) == 0 {
+		return nil
+	}
+
+	var stack Stack
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		if frame.Function != "" && !strings.HasPrefix(frame.Function, "runtime.") {
+			stack = append(stack, Frame{Function: frame.Function, File: frame.File, Line: frame.Line})
+		}
+		if !more {
+			break
+		}
+	}
+	return stack
+}
+
+// stackTracePCs calls a StackTrace method returning a slice of
+// uintptr-based frames, without depending on the package that defines it.
+func stackTracePCs(err error) []uintptr {
+	m := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !m.IsValid() {
+		return nil
+	}
+	t := m.Type()
+	if t.NumIn() != 0 || t.NumOut() != 1 || t.Out(0).Kind() != reflect.Slice || t.Out(0).Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+
+	st := m.Call(nil)[0]
+	pcs := make([]uintptr, st.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(st.Index(i).Uint())
+	}
+	return pcs
+}
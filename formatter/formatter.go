@@ -1,16 +1,138 @@
 package formatter
 
-import "context"
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+)
 
 // Entry represents a log entry for formatting.
 // This is defined here to avoid import cycles.
 type Entry struct {
-	Time    string
+	Time time.Time
+
+	// TimeFormat is the logger's default timestamp format, used by
+	// formatters that have none of their own. See FormatTime.
+	TimeFormat string
+
 	Level   string
 	Message string
-	Fields  map[string]any
-	Caller  string
+
+	// Attrs holds the fields in order: logger fields, then context fields,
+	// then call-site fields. Keys are unique; a later value for the same key
+	// replaces the earlier one in place. Groups are kept as slog group values.
+	Attrs []slog.Attr
+
+	// Fields is a map view of Attrs kept for hooks written against it.
+	// Groups appear as nested Group maps. Changes made to it by a hook are
+	// copied back into Attrs by the logger.
+	Fields map[string]any
+
+	// Caller is the file and line that logged the entry, formatted as
+	// configured on the logger. Source holds the same caller as a
+	// structured frame, with the same file path and the full function name.
+	Caller string
+	Source Frame
+
 	Context context.Context `json:"-"`
+
+	// Cache, if set, holds the first Cache.Len() attributes of Attrs,
+	// usually the fields of a logger created with With, along with their
+	// encodings. Formatters may write the cached encoding instead of
+	// encoding those attributes again. Whoever changes those attributes
+	// must clear it.
+	Cache *AttrCache `json:"-"`
+}
+
+// Clone returns a copy of the entry that does not share Attrs or Fields
+// with e. Sinks that keep an entry after Write returns must keep a clone,
+// since the logger reuses entries.
+func (e *Entry) Clone() *Entry {
+	c := *e
+	c.Attrs = slices.Clone(e.Attrs)
+	if e.Fields != nil {
+		c.Fields = cloneGroup(e.Fields)
+	}
+	return &c
+}
+
+func cloneGroup(fields map[string]any) map[string]any {
+	out := make(map[string]any, len(fields))
+	for k, v := range fields {
+		if g, ok := v.(Group); ok {
+			v = Group(cloneGroup(g))
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// Attributes returns the fields of the entry in output order.
+// Entries built without Attrs fall back to Fields, sorted by key.
+func (e *Entry) Attributes() []slog.Attr {
+	if e.Attrs != nil || len(e.Fields) == 0 {
+		return e.Attrs
+	}
+	return AttrsFromFields(e.Fields)
+}
+
+// Timestamp formats understood by FormatTime in addition to time layouts.
+const (
+	TimestampUnix      = "unix"
+	TimestampUnixMilli = "unixmilli"
+	TimestampUnixNano  = "unixnano"
+)
+
+// FormatTime renders the entry's time using format, falling back to the
+// entry's TimeFormat and then to RFC3339Nano. The format is either a time
+// layout or one of the Timestamp constants, in which case the result is an
+// integer and numeric is true. If utc is set, the time is converted to UTC
+// first. A zero time renders as an empty string.
+func (e *Entry) FormatTime(format string, utc bool) (s string, numeric bool) {
+	if e.Time.IsZero() {
+		return "", false
+	}
+	return string(e.appendTime(nil, format, utc)), isUnixFormat(e.timeLayout(format))
+}
+
+// timeLayout returns the format FormatTime uses for the given format.
+func (e *Entry) timeLayout(format string) string {
+	if format == "" {
+		format = e.TimeFormat
+	}
+	if format == "" {
+		format = time.RFC3339Nano
+	}
+	return format
+}
+
+func isUnixFormat(format string) bool {
+	return format == TimestampUnix || format == TimestampUnixMilli || format == TimestampUnixNano
+}
+
+// appendTime appends the result of FormatTime to dst.
+func (e *Entry) appendTime(dst []byte, format string, utc bool) []byte {
+	if e.Time.IsZero() {
+		return dst
+	}
+
+	switch layout := e.timeLayout(format); layout {
+	case TimestampUnix:
+		return strconv.AppendInt(dst, e.Time.Unix(), 10)
+	case TimestampUnixMilli:
+		return strconv.AppendInt(dst, e.Time.UnixMilli(), 10)
+	case TimestampUnixNano:
+		return strconv.AppendInt(dst, e.Time.UnixNano(), 10)
+	default:
+		t := e.Time
+		if utc {
+			t = t.UTC()
+		}
+		return t.AppendFormat(dst, layout)
+	}
 }
 
 // Formatter defines the interface for formatting log entries.
@@ -18,3 +140,61 @@ type Formatter interface {
 	// Format formats a log entry into a byte slice.
 	Format(entry *Entry) ([]byte, error)
 }
+
+// AppendFormatter is implemented by formatters that can append an entry
+// to an existing buffer. Sinks use it to reuse their buffers.
+type AppendFormatter interface {
+	Formatter
+
+	// AppendFormat appends the formatted entry to dst.
+	AppendFormat(dst []byte, entry *Entry) ([]byte, error)
+}
+
+// Group holds the attributes of an slog.Group as a nested field.
+// JSONFormatter renders it as an object and TextFormatter as dotted keys.
+type Group map[string]any
+
+// FieldMap returns the map view of attrs, with groups as nested Group maps.
+func FieldMap(attrs []slog.Attr) map[string]any {
+	fields := make(map[string]any, len(attrs))
+	for _, a := range attrs {
+		fields[a.Key] = FieldValue(a.Value)
+	}
+	return fields
+}
+
+// FieldValue returns the map view of a single value.
+func FieldValue(v slog.Value) any {
+	if v.Kind() != slog.KindGroup {
+		return v.Any()
+	}
+	attrs := v.Group()
+	g := make(Group, len(attrs))
+	for _, a := range attrs {
+		g[a.Key] = FieldValue(a.Value)
+	}
+	return g
+}
+
+// FieldAttr converts a map field back into an attribute, restoring groups.
+func FieldAttr(key string, v any) slog.Attr {
+	if g, ok := v.(Group); ok {
+		return slog.Attr{Key: key, Value: slog.GroupValue(AttrsFromFields(g)...)}
+	}
+	return slog.Any(key, v)
+}
+
+// AttrsFromFields converts a field map into attributes sorted by key.
+func AttrsFromFields(fields map[string]any) []slog.Attr {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, FieldAttr(k, fields[k]))
+	}
+	return attrs
+}
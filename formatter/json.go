@@ -1,46 +1,116 @@
 package formatter
 
 import (
+	"bytes"
 	"encoding/json"
+	"log/slog"
+	"strconv"
 )
 
 // JSONFormatter formats log entries as JSON.
 type JSONFormatter struct {
 	// PrettyPrint enables indented JSON output.
 	PrettyPrint bool
-}
 
-// jsonEntry is the JSON representation of a log entry.
-type jsonEntry struct {
-	Time    string         `json:"time"`
-	Level   string         `json:"level"`
-	Message string         `json:"message"`
-	Caller  string         `json:"caller,omitempty"`
-	Fields  map[string]any `json:"fields,omitempty"`
+	// TimestampFormat is the layout for timestamps, or one of the
+	// Timestamp constants to emit Unix times as numbers.
+	// Defaults to the logger's format.
+	TimestampFormat string
+
+	// UTC renders timestamps in UTC instead of local time.
+	UTC bool
 }
 
+// jsonCacheKey identifies JSON encodings in an AttrCache. Field encoding
+// does not depend on the formatter's settings, so all JSON formatters
+// share it.
+const jsonCacheKey = "json"
+
 // Format formats the entry as JSON.
 func (f *JSONFormatter) Format(entry *Entry) ([]byte, error) {
-	je := jsonEntry{
-		Time:    entry.Time,
-		Level:   entry.Level,
-		Message: entry.Message,
-		Caller:  entry.Caller,
-		Fields:  entry.Fields,
+	return f.AppendFormat(nil, entry)
+}
+
+// AppendFormat appends the entry as a line of JSON to dst:
+// {"time":...,"level":...,"message":...,"caller":...,"fields":{...}}.
+// The caller is an object with function, file and line when the entry
+// has a Source, and the Caller string otherwise.
+func (f *JSONFormatter) AppendFormat(dst []byte, entry *Entry) ([]byte, error) {
+	start := len(dst)
+
+	dst = append(dst, `{"time":`...)
+	if !entry.Time.IsZero() && isUnixFormat(entry.timeLayout(f.TimestampFormat)) {
+		dst = entry.appendTime(dst, f.TimestampFormat, f.UTC)
+	} else {
+		// Time layouts rarely produce characters that need escaping,
+		// so the time is written in place and only escaped if needed.
+		mark := len(dst)
+		dst = append(dst, '"')
+		dst = entry.appendTime(dst, f.TimestampFormat, f.UTC)
+		if needsEscape(dst[mark+1:]) {
+			dst = appendJSONString(dst[:mark], string(dst[mark+1:]))
+		} else {
+			dst = append(dst, '"')
+		}
+	}
+
+	dst = append(dst, `,"level":`...)
+	dst = appendJSONString(dst, entry.Level)
+	dst = append(dst, `,"message":`...)
+	dst = appendJSONString(dst, entry.Message)
+	if src := entry.Source; src.File != "" {
+		dst = append(dst, `,"caller":{"function":`...)
+		dst = appendJSONString(dst, src.Function)
+		dst = append(dst, `,"file":`...)
+		dst = appendJSONString(dst, src.File)
+		dst = append(dst, `,"line":`...)
+		dst = strconv.AppendInt(dst, int64(src.Line), 10)
+		dst = append(dst, '}')
+	} else if entry.Caller != "" {
+		dst = append(dst, `,"caller":`...)
+		dst = appendJSONString(dst, entry.Caller)
+	}
+
+	if attrs := entry.Attributes(); len(attrs) > 0 {
+		dst = append(dst, `,"fields":{`...)
+		cached, rest := cachedPrefix(entry, attrs, jsonCacheKey, encodeJSONAttrs)
+		dst = append(dst, cached...)
+		if len(cached) > 0 && len(rest) > 0 {
+			dst = append(dst, ',')
+		}
+
+		var err error
+		if dst, err = appendJSONAttrs(dst, rest); err != nil {
+			return nil, err
+		}
+		dst = append(dst, '}')
 	}
+	dst = append(dst, '}')
 
 	if f.PrettyPrint {
-		data, err := json.MarshalIndent(je, "", "  ")
-		if err != nil {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, dst[start:], "", "  "); err != nil {
 			return nil, err
 		}
-		return append(data, '\n'), nil
+		dst = append(dst[:start], buf.Bytes()...)
 	}
-	data, err := json.Marshal(je)
-	if err != nil {
-		return nil, err
+	return append(dst, '\n'), nil
+}
+
+// encodeJSONAttrs encodes attributes for an AttrCache.
+func encodeJSONAttrs(attrs []slog.Attr) ([]byte, bool) {
+	data, err := appendJSONAttrs(nil, attrs)
+	return data, err == nil
+}
+
+// needsEscape reports whether b cannot be written as a JSON string as is.
+func needsEscape(b []byte) bool {
+	for _, c := range b {
+		if c < 0x20 || c == '"' || c == '\\' || c == '<' || c == '>' || c == '&' || c >= 0x80 {
+			return true
+		}
 	}
-	return append(data, '\n'), nil
+	return false
 }
 
 // NewJSON creates a new JSON formatter.
@@ -0,0 +1,13 @@
+package formatter
+
+// Frame is a single call frame of a stack trace.
+type Frame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// Stack is a stack trace attached to an entry as a field, innermost frame
+// first. JSONFormatter renders it as an array of frames and TextFormatter
+// as an indented block below the entry.
+type Stack []Frame
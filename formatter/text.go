@@ -3,9 +3,10 @@ package formatter
 import (
 	"bytes"
 	"encoding/json"
-	"fmt"
-	"sort"
-	"strings"
+	"log/slog"
+	"strconv"
+
+	"github.com/godeh/sloggergo/level"
 )
 
 // TextFormatter formats log entries as human-readable text.
@@ -19,9 +20,13 @@ type TextFormatter struct {
 	// DisableCaller hides the caller information.
 	DisableCaller bool
 
-	// TimestampFormat is the format for timestamps.
+	// TimestampFormat is the layout for timestamps, or one of the
+	// Timestamp constants for Unix times. Defaults to the logger's format.
 	TimestampFormat string
 
+	// UTC renders timestamps in UTC instead of local time.
+	UTC bool
+
 	// PrettyPrint enables multi-line output for easier reading in development.
 	PrettyPrint bool
 }
@@ -38,102 +43,233 @@ const (
 	colorGray   = "\033[90m"
 )
 
+// Cache keys for text encodings in an AttrCache. Colors change the
+// encoding; pretty-printed fields are not cached.
+const (
+	textCacheKey      = "text"
+	textColorCacheKey = "text+color"
+)
+
+// textIndent indents pretty-printed fields and stack traces.
+const textIndent = "    "
+
 // Format formats the entry as text.
 func (f *TextFormatter) Format(entry *Entry) ([]byte, error) {
-	var buf bytes.Buffer
+	return f.AppendFormat(nil, entry)
+}
 
+// AppendFormat appends the entry as text to dst.
+func (f *TextFormatter) AppendFormat(buf []byte, entry *Entry) ([]byte, error) {
 	// Timestamp
-	if !f.DisableTimestamp && entry.Time != "" {
-		buf.WriteString(f.colorize(colorGray, entry.Time))
-		buf.WriteString(" ")
+	if !f.DisableTimestamp && !entry.Time.IsZero() {
+		buf = f.startColor(buf, colorGray)
+		buf = entry.appendTime(buf, f.TimestampFormat, f.UTC)
+		buf = f.endColor(buf)
+		buf = append(buf, ' ')
 	}
 
 	// Level
-	levelColor := f.getLevelColor(entry.Level)
-	buf.WriteString(f.colorize(levelColor, fmt.Sprintf("%-5s", entry.Level)))
-	buf.WriteString(" ")
+	buf = f.startColor(buf, f.getLevelColor(entry.Level))
+	buf = append(buf, entry.Level...)
+	for i := len(entry.Level); i < 5; i++ {
+		buf = append(buf, ' ')
+	}
+	buf = f.endColor(buf)
+	buf = append(buf, ' ')
 
 	// Caller
 	if !f.DisableCaller && entry.Caller != "" {
-		buf.WriteString(f.colorize(colorCyan, entry.Caller))
-		buf.WriteString(" ")
+		buf = f.startColor(buf, colorCyan)
+		buf = append(buf, entry.Caller...)
+		buf = f.endColor(buf)
+		buf = append(buf, ' ')
 	}
 
 	// Message
-	buf.WriteString(entry.Message)
+	buf = append(buf, entry.Message...)
 
 	// Fields
-	if len(entry.Fields) > 0 {
+	var stacks []stackField
+	if attrs := entry.Attributes(); len(attrs) > 0 {
 		if f.PrettyPrint {
-			buf.WriteString("\n")
-			// Sort keys for consistent output
-			keys := make([]string, 0, len(entry.Fields))
-			for k := range entry.Fields {
-				keys = append(keys, k)
-			}
-			sort.Strings(keys)
-
-			for _, k := range keys {
-				v := entry.Fields[k]
-				buf.WriteString(strings.Repeat(" ", 4)) // Indent
-				buf.WriteString(f.colorize(colorBlue, k))
-				buf.WriteString(": ")
-
-				// Pretty print complex values
-				jsonBytes, err := json.MarshalIndent(v, strings.Repeat(" ", 4), "  ")
-				if err == nil && (strings.HasPrefix(string(jsonBytes), "{") || strings.HasPrefix(string(jsonBytes), "[")) {
-					buf.WriteString(string(jsonBytes))
-				} else {
-					fmt.Fprintf(&buf, "%v", v)
-				}
-				buf.WriteString("\n")
+			buf = append(buf, '\n')
+			for _, a := range attrs {
+				flattenAttr("", a, func(key string, v slog.Value) {
+					if st, ok := stackValue(v); ok {
+						stacks = append(stacks, stackField{key, st})
+						return
+					}
+					buf = append(buf, textIndent...)
+					buf = f.appendColored(buf, colorBlue, key)
+					buf = append(buf, ": "...)
+
+					// Pretty print complex values
+					var jsonBytes []byte
+					var err error
+					if v.Kind() == slog.KindAny {
+						jsonBytes, err = json.MarshalIndent(v.Any(), textIndent, "  ")
+					}
+					if err == nil && (bytes.HasPrefix(jsonBytes, []byte("{")) || bytes.HasPrefix(jsonBytes, []byte("["))) {
+						buf = append(buf, jsonBytes...)
+					} else {
+						buf = appendTextValue(buf, v)
+					}
+					buf = append(buf, '\n')
+				})
 			}
 		} else {
-			buf.WriteString(" ")
-			first := true
-			// To ensure deterministic order for tests/sanity, let's sort keys even in non-pretty mode if it's cheap,
-			// or just iterate map. Map iteration is random.
-			// Let's stick to map iteration for perf unless sorted is needed?
-			// Standard behavior is usually random or use specific marshaler.
-			// Standard `text` formatter usually just loops.
-			for k, v := range entry.Fields {
-				if !first {
-					buf.WriteString(" ")
-				}
-				buf.WriteString(f.colorize(colorBlue, k))
-				buf.WriteString("=")
-				fmt.Fprintf(&buf, "%v", v)
-				first = false
+			cached, rest := cachedPrefix(entry, attrs, f.cacheKey(), f.encodeAttrs)
+			buf = append(buf, cached...)
+			for _, a := range rest {
+				flattenAttr("", a, func(key string, v slog.Value) {
+					if st, ok := stackValue(v); ok {
+						stacks = append(stacks, stackField{key, st})
+						return
+					}
+					buf = f.appendField(buf, key, v)
+				})
+			}
+		}
+	}
+
+	// Stack traces go below the entry, one indented block each.
+	if len(stacks) > 0 {
+		if !f.PrettyPrint {
+			buf = append(buf, '\n')
+		}
+		for _, sf := range stacks {
+			buf = f.appendStack(buf, sf.key, sf.stack)
+		}
+		if !f.PrettyPrint {
+			return buf, nil
+		}
+	}
+
+	return append(buf, '\n'), nil
+}
+
+// appendField appends a single key=value pair, preceded by a space.
+func (f *TextFormatter) appendField(buf []byte, key string, v slog.Value) []byte {
+	buf = append(buf, ' ')
+	buf = f.appendColored(buf, colorBlue, key)
+	buf = append(buf, '=')
+	return appendTextValue(buf, v)
+}
+
+func (f *TextFormatter) cacheKey() string {
+	if f.DisableColors {
+		return textCacheKey
+	}
+	return textColorCacheKey
+}
+
+// encodeAttrs encodes attributes for an AttrCache. Stack traces are
+// written below the entry, so attributes holding one are not cached.
+func (f *TextFormatter) encodeAttrs(attrs []slog.Attr) ([]byte, bool) {
+	var buf []byte
+	ok := true
+	for _, a := range attrs {
+		flattenAttr("", a, func(key string, v slog.Value) {
+			if _, isStack := stackValue(v); isStack {
+				ok = false
 			}
+			buf = f.appendField(buf, key, v)
+		})
+	}
+	return buf, ok
+}
+
+// stackField is a stack trace found among the fields of an entry.
+type stackField struct {
+	key   string
+	stack Stack
+}
+
+// stackValue returns the stack trace held by v, if any.
+func stackValue(v slog.Value) (Stack, bool) {
+	if v.Kind() != slog.KindAny {
+		return nil, false
+	}
+	st, ok := v.Any().(Stack)
+	return st, ok
+}
+
+// appendStack appends a stack trace as an indented block:
+//
+//	stacktrace:
+//	    main.handler
+//	        /app/main.go:42
+func (f *TextFormatter) appendStack(buf []byte, key string, stack Stack) []byte {
+	buf = append(buf, textIndent...)
+	buf = f.appendColored(buf, colorBlue, key)
+	buf = append(buf, ":\n"...)
+	for _, fr := range stack {
+		buf = append(buf, textIndent+textIndent...)
+		buf = append(buf, fr.Function...)
+		buf = append(buf, '\n')
+		buf = append(buf, textIndent+textIndent+textIndent...)
+		buf = append(buf, fr.File...)
+		buf = append(buf, ':')
+		buf = strconv.AppendInt(buf, int64(fr.Line), 10)
+		buf = append(buf, '\n')
+	}
+	return buf
+}
+
+// flattenAttr calls fn for every leaf value of an attribute, joining the
+// keys of nested groups with dots (http.status=200). Errors are expanded
+// into their message followed by the .type, .chain and .stack keys.
+func flattenAttr(prefix string, a slog.Attr, fn func(key string, v slog.Value)) {
+	key := a.Key
+	if prefix != "" {
+		key = prefix + "." + key
+	}
+	if err, ok := errorValue(a.Value); ok {
+		info := NewErrorInfo(err)
+		fn(key, slog.StringValue(info.Message))
+		fn(key+".type", slog.StringValue(info.Type))
+		if len(info.Chain) > 0 {
+			fn(key+".chain", slog.StringValue(info.chainString()))
+		}
+		if len(info.Stack) > 0 {
+			fn(key+".stack", slog.AnyValue(info.Stack))
 		}
+		return
+	}
+	if a.Value.Kind() != slog.KindGroup {
+		fn(key, a.Value)
+		return
 	}
+	for _, ga := range a.Value.Group() {
+		flattenAttr(key, ga, fn)
+	}
+}
 
-	buf.WriteString("\n")
-	return buf.Bytes(), nil
+func (f *TextFormatter) startColor(buf []byte, color string) []byte {
+	if f.DisableColors {
+		return buf
+	}
+	return append(buf, color...)
 }
 
-func (f *TextFormatter) colorize(color, text string) string {
+func (f *TextFormatter) endColor(buf []byte) []byte {
 	if f.DisableColors {
-		return text
-	}
-	return color + text + colorReset
-}
-
-func (f *TextFormatter) getLevelColor(level string) string {
-	switch level {
-	case "DEBUG":
-		return colorGray
-	case "INFO":
-		return colorGreen
-	case "WARN":
-		return colorYellow
-	case "ERROR":
-		return colorRed
-	case "FATAL":
-		return colorPurple
-	default:
-		return colorReset
+		return buf
+	}
+	return append(buf, colorReset...)
+}
+
+func (f *TextFormatter) appendColored(buf []byte, color, text string) []byte {
+	buf = f.startColor(buf, color)
+	buf = append(buf, text...)
+	return f.endColor(buf)
+}
+
+func (f *TextFormatter) getLevelColor(name string) string {
+	if color := level.Color(name); color != level.ColorNone {
+		return color
 	}
+	return colorReset
 }
 
 // NewText creates a new text formatter.
@@ -0,0 +1,175 @@
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// appendTextValue appends a field value for text output.
+// Durations and times use the same representation as appendJSONValue.
+func appendTextValue(dst []byte, v slog.Value) []byte {
+	switch v.Kind() {
+	case slog.KindString:
+		return append(dst, v.String()...)
+	case slog.KindInt64:
+		return strconv.AppendInt(dst, v.Int64(), 10)
+	case slog.KindUint64:
+		return strconv.AppendUint(dst, v.Uint64(), 10)
+	case slog.KindFloat64:
+		return strconv.AppendFloat(dst, v.Float64(), 'g', -1, 64)
+	case slog.KindBool:
+		return strconv.AppendBool(dst, v.Bool())
+	case slog.KindDuration:
+		return append(dst, v.Duration().String()...)
+	case slog.KindTime:
+		return v.Time().AppendFormat(dst, time.RFC3339Nano)
+	default:
+		return fmt.Appendf(dst, "%v", v.Any())
+	}
+}
+
+// appendJSONObject appends attributes as a JSON object, keeping their order.
+func appendJSONObject(dst []byte, attrs []slog.Attr) ([]byte, error) {
+	dst = append(dst, '{')
+	dst, err := appendJSONAttrs(dst, attrs)
+	if err != nil {
+		return nil, err
+	}
+	return append(dst, '}'), nil
+}
+
+// appendJSONAttrs appends attributes as the comma-separated members of a
+// JSON object, without the braces.
+func appendJSONAttrs(dst []byte, attrs []slog.Attr) ([]byte, error) {
+	for i, a := range attrs {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = appendJSONString(dst, a.Key)
+		dst = append(dst, ':')
+
+		var err error
+		if dst, err = appendJSONValue(dst, a.Value); err != nil {
+			return nil, err
+		}
+	}
+	return dst, nil
+}
+
+// appendJSONValue appends the JSON representation of v.
+// Durations become strings such as "1.5s" rather than nanosecond counts,
+// errors become ErrorInfo objects, and groups become nested objects.
+func appendJSONValue(dst []byte, v slog.Value) ([]byte, error) {
+	switch v.Kind() {
+	case slog.KindGroup:
+		return appendJSONObject(dst, v.Group())
+	case slog.KindString:
+		return appendJSONString(dst, v.String()), nil
+	case slog.KindInt64:
+		return strconv.AppendInt(dst, v.Int64(), 10), nil
+	case slog.KindUint64:
+		return strconv.AppendUint(dst, v.Uint64(), 10), nil
+	case slog.KindFloat64:
+		return appendJSONFloat(dst, v.Float64())
+	case slog.KindBool:
+		return strconv.AppendBool(dst, v.Bool()), nil
+	case slog.KindDuration:
+		return appendJSONString(dst, v.Duration().String()), nil
+	case slog.KindTime:
+		dst = append(dst, '"')
+		dst = v.Time().AppendFormat(dst, time.RFC3339Nano)
+		return append(dst, '"'), nil
+	case slog.KindLogValuer:
+		return appendJSONValue(dst, v.Resolve())
+	}
+
+	var data []byte
+	var err error
+	if e, ok := errorValue(v); ok {
+		data, err = json.Marshal(NewErrorInfo(e))
+	} else {
+		data, err = json.Marshal(v.Any())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return append(dst, data...), nil
+}
+
+// appendJSONFloat formats f the way encoding/json does.
+func appendJSONFloat(dst []byte, f float64) ([]byte, error) {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil, fmt.Errorf("json: unsupported value: %s", strconv.FormatFloat(f, 'g', -1, 64))
+	}
+
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	dst = strconv.AppendFloat(dst, f, format, -1, 64)
+	if format == 'e' {
+		// Shorten e-09 to e-9, as encoding/json does.
+		if n := len(dst); n >= 4 && dst[n-4] == 'e' && dst[n-3] == '-' && dst[n-2] == '0' {
+			dst[n-2] = dst[n-1]
+			dst = dst[:n-1]
+		}
+	}
+	return dst, nil
+}
+
+const hex = "0123456789abcdef"
+
+// appendJSONString appends s as a JSON string, escaped the same way as
+// encoding/json: HTML characters and invalid UTF-8 are escaped too.
+func appendJSONString(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			if b >= 0x20 && b != '"' && b != '\\' && b != '<' && b != '>' && b != '&' {
+				i++
+				continue
+			}
+			dst = append(dst, s[start:i]...)
+			switch b {
+			case '"', '\\':
+				dst = append(dst, '\\', b)
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', hex[b>>4], hex[b&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		c, size := utf8.DecodeRuneInString(s[i:])
+		if c == utf8.RuneError && size == 1 {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, `\ufffd`...)
+			i += size
+			start = i
+			continue
+		}
+		if c == '\u2028' || c == '\u2029' {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, '\\', 'u', '2', '0', '2', hex[c&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
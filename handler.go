@@ -0,0 +1,105 @@
+package sloggergo
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"slices"
+	"time"
+
+	"github.com/godeh/sloggergo/level"
+)
+
+// Handler is an slog.Handler backed by a Logger.
+// Records logged through log/slog are sent to the logger's sinks,
+// hooks and context extractor.
+//
+//	slog.SetDefault(slog.New(sloggergo.NewHandler(logger)))
+type Handler struct {
+	logger *Logger
+	attrs  []slog.Attr
+	groups []string
+}
+
+// NewHandler creates a new slog.Handler that writes through the given logger.
+func NewHandler(logger *Logger) *Handler {
+	return &Handler{logger: logger}
+}
+
+// Enabled reports whether the logger accepts records at the given level.
+func (h *Handler) Enabled(_ context.Context, lvl slog.Level) bool {
+	return h.logger.enabled(level.FromSlog(lvl))
+}
+
+// Handle forwards the record to the logger.
+// slog levels map onto Level one to one, so custom slog levels keep their
+// severity. Records never exit the process, even at FatalLevel.
+// The caller is taken from the record's PC, so it points at the code
+// that called slog rather than at the handler.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	attrs := make([]slog.Attr, 0, len(h.attrs)+1)
+	attrs = append(attrs, h.attrs...)
+
+	if r.NumAttrs() > 0 {
+		recAttrs := make([]slog.Attr, 0, r.NumAttrs())
+		r.Attrs(func(a slog.Attr) bool {
+			recAttrs = append(recAttrs, a)
+			return true
+		})
+		attrs = append(attrs, nestAttrs(h.groups, recAttrs)...)
+	}
+
+	var caller runtime.Frame
+	if h.logger.addCaller && r.PC != 0 {
+		caller, _ = runtime.CallersFrames([]uintptr{r.PC}).Next()
+	}
+
+	t := r.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+
+	h.logger.write(ctx, level.FromSlog(r.Level), t, r.Message, caller, attrs)
+	return nil
+}
+
+// WithAttrs returns a handler that adds the given attributes to every record.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	h2 := h.clone()
+	h2.attrs = append(h2.attrs, nestAttrs(h.groups, attrs)...)
+	return h2
+}
+
+// WithGroup returns a handler that nests all later attributes under name.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := h.clone()
+	h2.groups = append(h2.groups, name)
+	return h2
+}
+
+func (h *Handler) clone() *Handler {
+	return &Handler{
+		logger: h.logger,
+		attrs:  slices.Clip(h.attrs),
+		groups: slices.Clip(h.groups),
+	}
+}
+
+// nestAttrs wraps attrs in one group per name, outermost first.
+// The groups hold a copy of attrs, so the caller's slice does not escape.
+func nestAttrs(groups []string, attrs []slog.Attr) []slog.Attr {
+	if len(groups) == 0 {
+		return attrs
+	}
+	nested := slices.Clone(attrs)
+	for i := len(groups) - 1; i >= 0; i-- {
+		nested = []slog.Attr{{Key: groups[i], Value: slog.GroupValue(nested...)}}
+	}
+	return nested
+}
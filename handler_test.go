@@ -0,0 +1,87 @@
+package sloggergo
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/godeh/sloggergo/formatter"
+)
+
+func TestHandlerForwardsRecords(t *testing.T) {
+	mock := &mockSink{}
+	var hooked bool
+	log := New(
+		WithLevel(InfoLevel),
+		WithSink(mock),
+		WithHook(func(ctx context.Context, entry *formatter.Entry) error {
+			hooked = true
+			return nil
+		}),
+	)
+
+	sl := slog.New(NewHandler(log))
+	sl.Debug("hidden")
+	sl.Warn("disk almost full", slog.Int("percent", 91))
+
+	if mock.Len() != 1 {
+		t.Fatalf("expected 1 entry, got %d", mock.Len())
+	}
+	entry := mock.entries[0]
+	if entry.Level != "WARN" {
+		t.Errorf("expected level WARN, got %s", entry.Level)
+	}
+	if entry.Fields["percent"] != int64(91) {
+		t.Errorf("expected percent=91, got %v", entry.Fields["percent"])
+	}
+	if !strings.HasPrefix(entry.Caller, "handler_test.go:") {
+		t.Errorf("expected caller in handler_test.go, got %q", entry.Caller)
+	}
+	if !hooked {
+		t.Error("expected hook to run for slog records")
+	}
+}
+
+func TestHandlerEnabled(t *testing.T) {
+	h := NewHandler(New(WithLevel(WarnLevel)))
+
+	tests := []struct {
+		level slog.Level
+		want  bool
+	}{
+		{slog.LevelDebug, false},
+		{slog.LevelInfo, false},
+		{slog.LevelWarn, true},
+		{slog.LevelError, true},
+		{slog.LevelError + 4, true},
+	}
+
+	for _, tt := range tests {
+		if got := h.Enabled(context.Background(), tt.level); got != tt.want {
+			t.Errorf("Enabled(%v) = %v, want %v", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestHandlerWithAttrsAndGroup(t *testing.T) {
+	mock := &mockSink{}
+	log := New(WithSink(mock))
+
+	sl := slog.New(NewHandler(log)).With("service", "api").WithGroup("req")
+	sl.Info("handled", slog.String("method", "GET"))
+
+	if mock.Len() != 1 {
+		t.Fatalf("expected 1 entry, got %d", mock.Len())
+	}
+	fields := mock.entries[0].Fields
+	if fields["service"] != "api" {
+		t.Errorf("expected service=api, got %v", fields["service"])
+	}
+	if _, ok := fields["req"]; !ok {
+		t.Errorf("expected req group, got %v", fields)
+	}
+	if _, ok := fields["method"]; ok {
+		t.Error("expected method to be nested under req")
+	}
+}
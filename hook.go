@@ -0,0 +1,263 @@
+package sloggergo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/godeh/sloggergo/formatter"
+)
+
+// ErrDropEntry is returned by a hook to drop the entry on purpose.
+// Any other error is passed to the ErrorHandler and the entry is still
+// written.
+var ErrDropEntry = errors.New("sloggergo: entry dropped by hook")
+
+// Hook is a function that can intercept and modify log entries before they
+// reach the sinks. Returning ErrDropEntry drops the entry.
+// Hooks change entry.Fields; each one that runs costs a copy of the fields
+// to find what it changed, so scope hooks with HookMinLevel or HookLevels
+// on hot paths.
+type Hook func(ctx context.Context, entry *formatter.Entry) error
+
+// PostHook is called after an entry has been handed to every sink.
+// err joins the errors returned by the sinks, and is nil if all succeeded.
+// The entry must not be modified, and must be cloned if it is kept.
+type PostHook func(ctx context.Context, entry *formatter.Entry, err error)
+
+// HookOption configures a hook.
+type HookOption func(*hook)
+
+// HookName names a hook so that it can be found by Hooks and removed with
+// RemoveHook. Adding a hook with the name of an existing one replaces it
+// in place.
+func HookName(name string) HookOption {
+	return func(h *hook) {
+		h.name = name
+	}
+}
+
+// HookMinLevel runs the hook only for entries at or above min.
+func HookMinLevel(min Level) HookOption {
+	return func(h *hook) {
+		h.min = min
+		h.hasMin = true
+	}
+}
+
+// HookLevels runs the hook only for entries at one of the given levels.
+func HookLevels(levels ...Level) HookOption {
+	return func(h *hook) {
+		h.levels = levels
+	}
+}
+
+// HookInfo describes a hook registered on a logger.
+type HookInfo struct {
+	Name string
+	Post bool
+}
+
+// WithHook adds a hook to the logger. Hooks run in the order they were
+// added.
+func WithHook(fn Hook, opts ...HookOption) Option {
+	return func(l *Logger) {
+		l.addHook(newHook(fn, nil, opts))
+	}
+}
+
+// WithPostHook adds a hook that runs after the entry has been written.
+func WithPostHook(fn PostHook, opts ...HookOption) Option {
+	return func(l *Logger) {
+		l.addHook(newHook(nil, fn, opts))
+	}
+}
+
+// AddHook adds a hook to the logger.
+// Loggers already derived from this one do not receive it.
+func (l *Logger) AddHook(fn Hook, opts ...HookOption) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.addHook(newHook(fn, nil, opts))
+}
+
+// AddPostHook adds a post-write hook to the logger.
+// Loggers already derived from this one do not receive it.
+func (l *Logger) AddPostHook(fn PostHook, opts ...HookOption) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.addHook(newHook(nil, fn, opts))
+}
+
+// RemoveHook removes the hook with the given name and reports whether
+// there was one.
+func (l *Logger) RemoveHook(name string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	hs := l.loadHooks()
+	if hs == nil || name == "" {
+		return false
+	}
+	pre := slices.DeleteFunc(slices.Clone(hs.pre), func(h *hook) bool { return h.name == name })
+	post := slices.DeleteFunc(slices.Clone(hs.post), func(h *hook) bool { return h.name == name })
+	if len(pre) == len(hs.pre) && len(post) == len(hs.post) {
+		return false
+	}
+	l.hooks.Store(&hookSet{pre: pre, post: post})
+	return true
+}
+
+// Hooks lists the hooks on the logger in the order they run: hooks first,
+// then post-write hooks.
+func (l *Logger) Hooks() []HookInfo {
+	hs := l.loadHooks()
+	if hs == nil {
+		return nil
+	}
+	infos := make([]HookInfo, 0, len(hs.pre)+len(hs.post))
+	for _, h := range hs.pre {
+		infos = append(infos, HookInfo{Name: h.name})
+	}
+	for _, h := range hs.post {
+		infos = append(infos, HookInfo{Name: h.name, Post: true})
+	}
+	return infos
+}
+
+// hook is a Hook or PostHook with its name and level filter.
+type hook struct {
+	name   string
+	fn     Hook
+	post   PostHook
+	min    Level
+	hasMin bool
+	levels []Level
+}
+
+func newHook(fn Hook, post PostHook, opts []HookOption) *hook {
+	h := &hook{fn: fn, post: post}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// accepts reports whether the hook runs for entries at the given level.
+func (h *hook) accepts(level Level) bool {
+	if h.hasMin && level < h.min {
+		return false
+	}
+	return len(h.levels) == 0 || slices.Contains(h.levels, level)
+}
+
+// hookSet is an immutable list of hooks, replaced as a whole when a hook
+// is added or removed so that it can be read without locking.
+type hookSet struct {
+	pre  []*hook
+	post []*hook
+}
+
+// addHook stores a copy of the hook list with h added, or replacing the
+// hook of the same name.
+func (l *Logger) addHook(h *hook) {
+	var hs hookSet
+	if old := l.loadHooks(); old != nil {
+		hs = hookSet{pre: slices.Clone(old.pre), post: slices.Clone(old.post)}
+	}
+	list := &hs.pre
+	if h.post != nil {
+		list = &hs.post
+	}
+	if h.name != "" {
+		named := func(o *hook) bool { return o.name == h.name }
+		if i := slices.IndexFunc(*list, named); i >= 0 {
+			(*list)[i] = h
+			l.hooks.Store(&hs)
+			return
+		}
+		// A name is unique across hooks and post-write hooks.
+		hs.pre = slices.DeleteFunc(hs.pre, named)
+		hs.post = slices.DeleteFunc(hs.post, named)
+	}
+	*list = append(*list, h)
+	l.hooks.Store(&hs)
+}
+
+// loadHooks returns the current hook list, which must not be modified.
+func (l *Logger) loadHooks() *hookSet {
+	return l.hooks.Load()
+}
+
+// runHooks runs the hooks on the entry and reports whether it should be
+// written. Changes a hook makes to entry.Fields are copied back into
+// entry.Attrs before the next hook runs.
+func (l *Logger) runHooks(ctx context.Context, level Level, entry *formatter.Entry) bool {
+	hs := l.loadHooks()
+	if hs == nil {
+		return true
+	}
+	for _, h := range hs.pre {
+		if !h.accepts(level) {
+			continue
+		}
+		before, attrs := cloneFields(entry.Fields), entry.Attrs
+		err := h.fn(ctx, entry)
+		if errors.Is(err, ErrDropEntry) {
+			return false
+		}
+		if err != nil {
+			l.handleError(h.wrap(err))
+		}
+		syncFields(entry, before)
+
+		// Hooks may change any field, including the logger's own; the
+		// cache only survives if they left those alone.
+		if c := entry.Cache; c != nil && !prefixUnchanged(c.Len(), attrs, entry, before) {
+			entry.Cache = nil
+		}
+	}
+	return true
+}
+
+// writeEntry hands the entry to every sink, then runs the post-write hooks
+// with the outcome.
+func (l *Logger) writeEntry(ctx context.Context, level Level, entry *formatter.Entry) {
+	var errs []error
+	for _, s := range l.loadSinks() {
+		if err := s.Write(entry); err != nil {
+			l.handleError(err)
+			errs = append(errs, err)
+		}
+	}
+
+	hs := l.loadHooks()
+	if hs == nil {
+		return
+	}
+	var err error
+	if len(errs) > 0 {
+		err = errors.Join(errs...)
+	}
+	for _, h := range hs.post {
+		if h.accepts(level) {
+			h.post(ctx, entry, err)
+		}
+	}
+}
+
+// handleError passes err to the error handler, if there is one.
+func (l *Logger) handleError(err error) {
+	if l.errorHandler != nil {
+		l.errorHandler(err)
+	}
+}
+
+// wrap adds the hook's name to an error it returned.
+func (h *hook) wrap(err error) error {
+	if h.name == "" {
+		return fmt.Errorf("hook: %w", err)
+	}
+	return fmt.Errorf("hook %s: %w", h.name, err)
+}
@@ -0,0 +1,182 @@
+package sloggergo
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/godeh/sloggergo/formatter"
+)
+
+func TestHookErrors(t *testing.T) {
+	mock := &mockSink{}
+	var handled []error
+	logger := New(
+		WithSink(mock),
+		WithErrorHandler(func(err error) { handled = append(handled, err) }),
+		WithHook(func(ctx context.Context, entry *formatter.Entry) error {
+			if entry.Message == "drop me" {
+				return ErrDropEntry
+			}
+			return nil
+		}),
+		WithHook(func(ctx context.Context, entry *formatter.Entry) error {
+			return errors.New("enricher failed")
+		}, HookName("enricher")),
+	)
+
+	logger.Info("drop me")
+	logger.Info("keep me")
+
+	if mock.Len() != 1 || mock.entries[0].Message != "keep me" {
+		t.Fatalf("expected only the kept entry, got %d entries", mock.Len())
+	}
+	if len(handled) != 1 || handled[0].Error() != "hook enricher: enricher failed" {
+		t.Errorf("expected the hook error to be reported once, got %v", handled)
+	}
+}
+
+func TestHookLevels(t *testing.T) {
+	var seen []string
+	record := func(ctx context.Context, entry *formatter.Entry) error {
+		seen = append(seen, entry.Message)
+		return nil
+	}
+	logger := New(
+		WithLevel(TraceLevel),
+		WithSink(&mockSink{}),
+		WithHook(record, HookMinLevel(WarnLevel)),
+		WithHook(record, HookLevels(DebugLevel)),
+	)
+
+	logger.Debug("debug")
+	logger.Info("info")
+	logger.Error("error")
+
+	if want := []string{"debug", "error"}; !slices.Equal(seen, want) {
+		t.Errorf("expected hooks to run for %v, got %v", want, seen)
+	}
+}
+
+func TestPostHook(t *testing.T) {
+	var outcomes []error
+	logger := New(
+		WithSink(&mockSink{}),
+		WithSink(failingSink{}),
+		WithPostHook(func(ctx context.Context, entry *formatter.Entry, err error) {
+			outcomes = append(outcomes, err)
+		}),
+	)
+
+	logger.Info("hello")
+
+	if len(outcomes) != 1 || !errors.Is(outcomes[0], os.ErrClosed) {
+		t.Errorf("expected the post hook to see the sink error, got %v", outcomes)
+	}
+}
+
+func TestNamedHooks(t *testing.T) {
+	mock := &mockSink{}
+	logger := New(WithSink(mock))
+
+	tag := func(value string) Hook {
+		return func(ctx context.Context, entry *formatter.Entry) error {
+			entry.Fields["tag"] = value
+			return nil
+		}
+	}
+	logger.AddHook(tag("a"), HookName("tag"))
+	logger.AddHook(tag("b"), HookName("tag"))
+	logger.AddPostHook(func(context.Context, *formatter.Entry, error) {}, HookName("audit"))
+
+	want := []HookInfo{{Name: "tag"}, {Name: "audit", Post: true}}
+	if got := logger.Hooks(); !slices.Equal(got, want) {
+		t.Fatalf("expected hooks %v, got %v", want, got)
+	}
+
+	logger.Info("first")
+	if !logger.RemoveHook("tag") {
+		t.Fatal("expected RemoveHook to find the hook")
+	}
+	if logger.RemoveHook("tag") {
+		t.Error("expected the second RemoveHook to find nothing")
+	}
+	logger.Info("second")
+
+	if got := mock.entries[0].Fields["tag"]; got != "b" {
+		t.Errorf("expected the replacement hook to run, got tag=%v", got)
+	}
+	if _, ok := mock.entries[1].Fields["tag"]; ok {
+		t.Error("expected the removed hook not to run")
+	}
+}
+
+func TestAsyncHooks(t *testing.T) {
+	mock := &mockSink{}
+	written := make(chan struct{}, 1)
+	logger := New(
+		WithSink(mock),
+		WithHook(func(ctx context.Context, entry *formatter.Entry) error {
+			return ErrDropEntry
+		}, HookLevels(DebugLevel)),
+		WithPostHook(func(context.Context, *formatter.Entry, error) {
+			written <- struct{}{}
+		}),
+	)
+	logger.SetLevel(DebugLevel)
+	async := NewAsync(logger)
+	defer async.Close()
+
+	async.Debug("dropped")
+	async.Info("kept")
+
+	select {
+	case <-written:
+	case <-time.After(time.Second):
+		t.Fatal("post hook did not run")
+	}
+	if mock.Len() != 1 || mock.entries[0].Message != "kept" {
+		t.Errorf("expected only the kept entry, got %d entries", mock.Len())
+	}
+}
+
+func TestHookKeepsFieldCache(t *testing.T) {
+	mock := &mockSink{}
+	log := New(WithSink(mock)).With("service", "api")
+	log.AddHook(func(_ context.Context, entry *formatter.Entry) error {
+		entry.Fields["hooked"] = true
+		if entry.Message == "rename" {
+			entry.Fields["service"] = "web"
+		}
+		return nil
+	})
+
+	log.Info("keep")
+	log.Info("rename")
+
+	if mock.entries[0].Cache == nil {
+		t.Error("expected the field cache to survive a hook that leaves logger fields alone")
+	}
+	if mock.entries[1].Cache != nil {
+		t.Error("expected the field cache to be dropped when a hook changes a logger field")
+	}
+	if got := mock.entries[1].Fields["service"]; got != "web" {
+		t.Errorf("expected the hook's change, got %v", got)
+	}
+}
+
+func TestHookUncomparableValues(t *testing.T) {
+	mock := &mockSink{}
+	log := New(WithSink(mock), WithHook(func(context.Context, *formatter.Entry) error { return nil }))
+
+	// The struct type is comparable, but the value it holds is not.
+	log.Info("x", slog.Any("h", struct{ V any }{[]int{1}}))
+
+	if mock.Len() != 1 {
+		t.Fatalf("expected 1 entry, got %d", mock.Len())
+	}
+}
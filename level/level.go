@@ -0,0 +1,230 @@
+// Package level defines log levels and the registry that maps them to
+// names and colors. It is shared by the logger, the formatters, the sinks
+// and the config package.
+package level
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Level represents the severity of a log entry.
+//
+// Levels use the numeric scale of log/slog, so converting between the two
+// is a plain type conversion:
+//
+//	TRACE    -8
+//	DEBUG    -4  slog.LevelDebug
+//	INFO      0  slog.LevelInfo
+//	NOTICE    2
+//	WARN      4  slog.LevelWarn
+//	ERROR     8  slog.LevelError
+//	CRITICAL 10
+//	PANIC    12
+//	FATAL    16
+type Level int
+
+const (
+	Trace    Level = -8
+	Debug    Level = Level(slog.LevelDebug)
+	Info     Level = Level(slog.LevelInfo)
+	Notice   Level = 2
+	Warn     Level = Level(slog.LevelWarn)
+	Error    Level = Level(slog.LevelError)
+	Critical Level = 10
+	Panic    Level = 12
+	Fatal    Level = 16
+)
+
+// ANSI colors for use with Register.
+const (
+	ColorNone    = ""
+	ColorRed     = "\033[31m"
+	ColorGreen   = "\033[32m"
+	ColorYellow  = "\033[33m"
+	ColorBlue    = "\033[34m"
+	ColorPurple  = "\033[35m"
+	ColorCyan    = "\033[36m"
+	ColorGray    = "\033[90m"
+	ColorBoldRed = "\033[1;31m"
+)
+
+type definition struct {
+	name  string
+	level Level
+	color string
+}
+
+var (
+	mu      sync.RWMutex
+	byLevel = map[Level]*definition{}
+	byName  = map[string]*definition{}
+	sorted  []*definition
+)
+
+func init() {
+	MustRegister("TRACE", Trace, ColorGray)
+	MustRegister("DEBUG", Debug, ColorGray)
+	MustRegister("INFO", Info, ColorGreen)
+	MustRegister("NOTICE", Notice, ColorCyan)
+	MustRegister("WARN", Warn, ColorYellow)
+	MustRegister("ERROR", Error, ColorRed)
+	MustRegister("CRITICAL", Critical, ColorBoldRed)
+	MustRegister("PANIC", Panic, ColorPurple)
+	MustRegister("FATAL", Fatal, ColorPurple)
+	byName["WARNING"] = byName["WARN"]
+}
+
+// Register defines a named level with the given severity and ANSI color.
+// Names are case-insensitive and stored in upper case. Registering the
+// same name and severity again only updates the color; reusing a name or
+// a severity that already belongs to another level is an error.
+func Register(name string, l Level, color string) error {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if name == "" {
+		return fmt.Errorf("level name is empty")
+	}
+	if strings.ContainsAny(name, "+- ") {
+		return fmt.Errorf("invalid level name: %s", name)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if d, ok := byName[name]; ok && d.level != l {
+		return fmt.Errorf("level name %s already used by level %d", name, d.level)
+	}
+	if d, ok := byLevel[l]; ok {
+		if d.name != name {
+			return fmt.Errorf("level %d already registered as %s", l, d.name)
+		}
+		d.color = color
+		return nil
+	}
+
+	d := &definition{name: name, level: l, color: color}
+	byLevel[l] = d
+	byName[name] = d
+	sorted = append(sorted, d)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].level < sorted[j].level })
+	return nil
+}
+
+// MustRegister is like Register but panics on error.
+func MustRegister(name string, l Level, color string) {
+	if err := Register(name, l, color); err != nil {
+		panic(err)
+	}
+}
+
+// Parse parses a level name such as "debug" or "WARN".
+// Like slog, it also accepts a name with an offset ("INFO+2") and a
+// plain number. Unknown names are reported as an error.
+func Parse(s string) (Level, error) {
+	name := strings.ToUpper(strings.TrimSpace(s))
+	if name == "" {
+		return Info, fmt.Errorf("invalid log level: %q", s)
+	}
+
+	if c := name[0]; c == '-' || c == '+' || (c >= '0' && c <= '9') {
+		if n, err := strconv.Atoi(name); err == nil {
+			return Level(n), nil
+		}
+	}
+
+	offset := 0
+	if i := strings.IndexAny(name, "+-"); i > 0 {
+		n, err := strconv.Atoi(name[i:])
+		if err != nil {
+			return Info, fmt.Errorf("invalid log level: %q", s)
+		}
+		name, offset = name[:i], n
+	}
+
+	mu.RLock()
+	d, ok := byName[name]
+	mu.RUnlock()
+	if !ok {
+		return Info, fmt.Errorf("invalid log level: %q", s)
+	}
+	return d.level + Level(offset), nil
+}
+
+// String returns the registered name of the level. Levels without a name
+// are shown relative to the closest named level below them, as in slog
+// ("INFO+1").
+func (l Level) String() string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if d, ok := byLevel[l]; ok {
+		return d.name
+	}
+	if len(sorted) == 0 {
+		return strconv.Itoa(int(l))
+	}
+
+	base := sorted[0]
+	for _, d := range sorted {
+		if d.level > l {
+			break
+		}
+		base = d
+	}
+	diff := int(l - base.level)
+	if diff >= 0 {
+		return base.name + "+" + strconv.Itoa(diff)
+	}
+	return base.name + strconv.Itoa(diff)
+}
+
+// MarshalText implements encoding.TextMarshaler using the level's name.
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using Parse.
+func (l *Level) UnmarshalText(data []byte) error {
+	parsed, err := Parse(string(data))
+	if err != nil {
+		return err
+	}
+	*l = parsed
+	return nil
+}
+
+// Slog returns the equivalent slog.Level.
+func (l Level) Slog() slog.Level {
+	return slog.Level(l)
+}
+
+// FromSlog returns the Level equivalent to an slog.Level.
+func FromSlog(l slog.Level) Level {
+	return Level(l)
+}
+
+// Color returns the ANSI color registered for a level name, as found in
+// formatter.Entry.Level. Unnamed levels use the color of the closest
+// named level below them.
+func Color(name string) string {
+	l, err := Parse(name)
+	if err != nil {
+		return ColorNone
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	color := ColorNone
+	for _, d := range sorted {
+		if d.level > l {
+			break
+		}
+		color = d.color
+	}
+	return color
+}
@@ -0,0 +1,32 @@
+package sloggergo
+
+import "sync/atomic"
+
+// LevelVar is a Level that can be read and changed atomically, like
+// slog.LevelVar. Pass it to several loggers with WithLevelVar to control
+// all of them at once.
+type LevelVar struct {
+	v atomic.Int64
+}
+
+// NewLevelVar creates a LevelVar set to level.
+func NewLevelVar(level Level) *LevelVar {
+	v := &LevelVar{}
+	v.Set(level)
+	return v
+}
+
+// Level returns the current level.
+func (v *LevelVar) Level() Level {
+	return Level(v.v.Load())
+}
+
+// Set changes the level.
+func (v *LevelVar) Set(level Level) {
+	v.v.Store(int64(level))
+}
+
+// String returns the name of the current level.
+func (v *LevelVar) String() string {
+	return "LevelVar(" + v.Level().String() + ")"
+}
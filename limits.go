@@ -0,0 +1,373 @@
+package sloggergo
+
+import (
+	"log/slog"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/godeh/sloggergo/formatter"
+)
+
+// Keys and markers added by Limits.
+const (
+	// FieldsDroppedKey holds the number of fields dropped by MaxFields.
+	FieldsDroppedKey = "fields_dropped"
+
+	// EntryTruncatedKey holds the encoded size of an entry that was cut
+	// down to MaxEntrySize.
+	EntryTruncatedKey = "entry_truncated"
+
+	// MaxDepthMarker replaces values nested deeper than MaxDepth.
+	MaxDepthMarker = "[max depth exceeded]"
+)
+
+// Limits bounds the size of log entries, so that a single bad call cannot
+// flood the sinks. Zero fields are not enforced. Limits are applied after
+// hooks have run, so they also cover fields that hooks add or grow, and
+// before the entry reaches the sinks.
+type Limits struct {
+	// MaxMessageLength is the maximum message length in bytes.
+	MaxMessageLength int
+
+	// MaxStringLength is the maximum length in bytes of string and []byte
+	// values and of error messages, including those nested in groups,
+	// maps, slices and structs.
+	MaxStringLength int
+
+	// MaxFields is the maximum number of fields. Later fields are dropped
+	// and FieldsDroppedKey, which counts towards the limit, records how
+	// many.
+	MaxFields int
+
+	// MaxDepth is the maximum nesting depth of fields: 1 allows only
+	// scalar fields, 2 allows one level of groups, maps, slices or
+	// structs, and so on. Deeper values are replaced by MaxDepthMarker.
+	MaxDepth int
+
+	// MaxEntrySize is the maximum size in bytes of the entry encoded as
+	// JSON. Fields are dropped from the end, and then the message is
+	// shortened, until it fits; EntryTruncatedKey records the original
+	// size. A limit too small for the time, level and caller alone is
+	// exceeded rather than met by growing the message. Measuring costs one
+	// extra JSON encoding per entry.
+	MaxEntrySize int
+}
+
+// WithLimits bounds the size of every entry.
+func WithLimits(limits Limits) Option {
+	return func(l *Logger) {
+		l.sizeLimits = limits
+	}
+}
+
+// applyLimits enforces the logger's size limits on an entry that hooks
+// are done with.
+func (l *Logger) applyLimits(entry *formatter.Entry) {
+	if l.sizeLimits.enabled() && l.sizeLimits.apply(entry) {
+		fillFields(entry)
+	}
+}
+
+func (lim *Limits) enabled() bool {
+	return *lim != Limits{}
+}
+
+// truncate cuts s to max bytes if it is longer, and appends a marker with
+// the original length. A max of zero or less means no limit.
+func truncate(s string, max int) string {
+	if max <= 0 || len(s) <= max {
+		return s
+	}
+	return cut(s, max, len(s))
+}
+
+// cut keeps the first n bytes of s, on a rune boundary, followed by a
+// truncation marker giving the original length.
+func cut(s string, n, original int) string {
+	for n > 0 && n < len(s) && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + "...[truncated " + strconv.Itoa(original) + " bytes]"
+}
+
+// shorten cuts s so that, marker included, it is by bytes shorter, or as
+// short as it can get. Strings no longer than the marker are left alone,
+// since cutting them would make them longer.
+func shorten(s string, by int) string {
+	overhead := len(cut(s, 0, len(s)))
+	if len(s) <= overhead {
+		return s
+	}
+	return cut(s, max(0, len(s)-by-overhead), len(s))
+}
+
+// apply enforces the limits on an entry's message and attributes, and
+// reports whether the attributes changed, in which case the caller must
+// rebuild entry.Fields.
+func (lim *Limits) apply(entry *formatter.Entry) bool {
+	entry.Message = truncate(entry.Message, lim.MaxMessageLength)
+	changed := false
+
+	if lim.MaxStringLength > 0 || lim.MaxDepth > 0 {
+		for i, a := range entry.Attrs {
+			if na, c := lim.attr(a, 1); c {
+				entry.Attrs[i] = na
+				lim.invalidate(entry, i)
+				changed = true
+			}
+		}
+	}
+
+	if lim.MaxFields > 0 && len(entry.Attrs) > lim.MaxFields {
+		// Keep a slot for the marker, so the total stays within the limit.
+		keep := lim.MaxFields - 1
+		dropped := len(entry.Attrs) - keep
+		lim.invalidate(entry, keep)
+		entry.Attrs = append(entry.Attrs[:keep], slog.Int(FieldsDroppedKey, dropped))
+		changed = true
+	}
+
+	if lim.MaxEntrySize > 0 && lim.fit(entry) {
+		changed = true
+	}
+	return changed
+}
+
+// invalidate drops the entry's cached encoding if attribute i is part of it.
+func (lim *Limits) invalidate(entry *formatter.Entry, i int) {
+	if entry.Cache != nil && i < entry.Cache.Len() {
+		entry.Cache = nil
+	}
+}
+
+// attr enforces the string and depth limits on an attribute at the given
+// depth, and reports whether it changed.
+func (lim *Limits) attr(a slog.Attr, depth int) (slog.Attr, bool) {
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindString:
+		if s := truncate(v.String(), lim.MaxStringLength); len(s) != len(v.String()) {
+			return slog.String(a.Key, s), true
+		}
+	case slog.KindGroup:
+		if lim.MaxDepth > 0 && depth >= lim.MaxDepth {
+			return slog.String(a.Key, MaxDepthMarker), true
+		}
+		group := v.Group()
+		var out []slog.Attr
+		for i, ga := range group {
+			na, changed := lim.attr(ga, depth+1)
+			if changed && out == nil {
+				out = append(make([]slog.Attr, 0, len(group)), group[:i]...)
+			}
+			if out != nil {
+				out = append(out, na)
+			}
+		}
+		if out != nil {
+			return slog.Attr{Key: a.Key, Value: slog.GroupValue(out...)}, true
+		}
+	case slog.KindAny:
+		if nv, changed := lim.value(v.Any(), depth); changed {
+			return slog.Any(a.Key, nv), true
+		}
+	}
+	return a, false
+}
+
+// value enforces the string and depth limits on a Go value, returning a
+// limited copy if anything had to change. Maps and structs that change
+// become map[string]any; structs use their JSON field names.
+func (lim *Limits) value(v any, depth int) (any, bool) {
+	switch x := v.(type) {
+	case nil:
+		return v, false
+	case error:
+		// Errors are logged as their message, so that is what gets cut.
+		if msg := x.Error(); lim.MaxStringLength > 0 && len(msg) > lim.MaxStringLength {
+			return truncate(msg, lim.MaxStringLength), true
+		}
+		return v, false
+	case string:
+		if s := truncate(x, lim.MaxStringLength); len(s) != len(x) {
+			return s, true
+		}
+		return v, false
+	case []byte:
+		if lim.MaxStringLength > 0 && len(x) > lim.MaxStringLength {
+			// Convert only the part that is kept, plus room to find a rune boundary.
+			head := string(x[:min(len(x), lim.MaxStringLength+utf8.UTFMax)])
+			return cut(head, lim.MaxStringLength, len(x)), true
+		}
+		return v, false
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+			return v, false
+		}
+		if nv, changed := lim.value(rv.Elem().Interface(), depth); changed {
+			return nv, true
+		}
+		return v, false
+	case reflect.Map, reflect.Struct, reflect.Slice, reflect.Array:
+	default:
+		return v, false
+	}
+
+	if isOpaque(rv.Type()) {
+		return v, false
+	}
+	if lim.MaxDepth > 0 && depth >= lim.MaxDepth {
+		return MaxDepthMarker, true
+	}
+
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return v, false
+		}
+		var out map[string]any
+		iter := rv.MapRange()
+		for iter.Next() {
+			nv, changed := lim.value(iter.Value().Interface(), depth+1)
+			if changed && out == nil {
+				out = make(map[string]any, rv.Len())
+				for _, k := range rv.MapKeys() {
+					out[k.String()] = rv.MapIndex(k).Interface()
+				}
+			}
+			if out != nil {
+				out[iter.Key().String()] = nv
+			}
+		}
+		if out != nil {
+			return out, true
+		}
+	case reflect.Struct:
+		t := rv.Type()
+		out := make(map[string]any, t.NumField())
+		changed := false
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			name, ok := jsonFieldName(sf)
+			if !ok {
+				continue
+			}
+			nv, c := lim.value(rv.Field(i).Interface(), depth+1)
+			out[name] = nv
+			changed = changed || c
+		}
+		if changed {
+			return out, true
+		}
+	case reflect.Slice, reflect.Array:
+		var out []any
+		for i := 0; i < rv.Len(); i++ {
+			nv, changed := lim.value(rv.Index(i).Interface(), depth+1)
+			if changed && out == nil {
+				out = make([]any, rv.Len())
+				for j := 0; j < i; j++ {
+					out[j] = rv.Index(j).Interface()
+				}
+			}
+			if out != nil {
+				out[i] = nv
+			}
+		}
+		if out != nil {
+			return out, true
+		}
+	}
+	return v, false
+}
+
+// isOpaque reports whether values of type t encode themselves, so that
+// their contents are not what gets logged.
+func isOpaque(t reflect.Type) bool {
+	return t == reflect.TypeFor[formatter.Stack]() ||
+		t.Implements(reflect.TypeFor[slog.LogValuer]()) ||
+		t.Implements(reflect.TypeFor[interface{ MarshalJSON() ([]byte, error) }]()) ||
+		t.Implements(reflect.TypeFor[interface{ MarshalText() ([]byte, error) }]())
+}
+
+// jsonFieldName returns the name encoding/json uses for a struct field, and
+// false if the field is not encoded.
+func jsonFieldName(sf reflect.StructField) (string, bool) {
+	if !sf.IsExported() {
+		return "", false
+	}
+	name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return "", false
+	case "":
+		return sf.Name, true
+	}
+	return name, true
+}
+
+// sizer measures entries by encoding them as JSON.
+var (
+	sizer     = formatter.NewJSON()
+	sizerPool = sync.Pool{New: func() any { return new([]byte) }}
+)
+
+func encodedSize(entry *formatter.Entry) int {
+	buf := sizerPool.Get().(*[]byte)
+	data, err := sizer.AppendFormat((*buf)[:0], entry)
+	n := len(data)
+	if err != nil {
+		n = 0
+	}
+	if cap(data) <= 64<<10 {
+		*buf = data
+		sizerPool.Put(buf)
+	}
+	return n
+}
+
+// fit drops fields from the end, and then shortens the message, until the
+// encoded entry is no larger than MaxEntrySize. Sizes of dropped fields are
+// measured one by one, so the whole entry is encoded at most three times.
+// It reports whether the entry was too large.
+func (lim *Limits) fit(entry *formatter.Entry) bool {
+	size := encodedSize(entry)
+	if size <= lim.MaxEntrySize {
+		return false
+	}
+
+	// The marker field takes some room of its own.
+	marker := slog.Int(EntryTruncatedKey, size)
+	size += attrSize(entry, marker)
+
+	for len(entry.Attrs) > 0 && size > lim.MaxEntrySize {
+		last := len(entry.Attrs) - 1
+		size -= attrSize(entry, entry.Attrs[last])
+		lim.invalidate(entry, last)
+		entry.Attrs = entry.Attrs[:last]
+	}
+	entry.Attrs = append(entry.Attrs, marker)
+
+	// Sizes measured field by field are close, not exact. The limit may
+	// still not be met if the rest of the entry is too large on its own.
+	if size = encodedSize(entry); size > lim.MaxEntrySize {
+		entry.Message = shorten(entry.Message, size-lim.MaxEntrySize)
+	}
+	return true
+}
+
+// attrSize returns roughly how much a adds to the encoded size of entry.
+func attrSize(entry *formatter.Entry, a slog.Attr) int {
+	without := *entry
+	without.Attrs, without.Fields, without.Cache = nil, nil, nil
+	with := without
+	with.Attrs = []slog.Attr{a}
+	// One more byte for the separator.
+	return encodedSize(&with) - encodedSize(&without) + 1
+}
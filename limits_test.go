@@ -0,0 +1,128 @@
+package sloggergo
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/godeh/sloggergo/formatter"
+)
+
+type response struct {
+	Status int    `json:"status"`
+	Body   []byte `json:"body"`
+	Meta   map[string]any
+}
+
+func TestLimits(t *testing.T) {
+	mock := &mockSink{}
+	logger := New(
+		WithSink(mock),
+		WithLimits(Limits{MaxMessageLength: 7, MaxStringLength: 4, MaxFields: 4, MaxDepth: 2}),
+	).With("service", "checkout")
+
+	resp := response{Status: 200, Body: []byte("0123456789"), Meta: map[string]any{"a": 1}}
+	logger.Info("request finished",
+		slog.Any("response", resp),
+		slog.Group("http", slog.String("method", "GET"), slog.Group("tls", slog.String("v", "1.3"))),
+		slog.String("extra", "x"),
+		slog.String("more", "y"),
+	)
+
+	e := mock.entries[0]
+	if e.Message != "request...[truncated 16 bytes]" {
+		t.Errorf("unexpected message %q", e.Message)
+	}
+	if e.Fields["service"] != "chec...[truncated 8 bytes]" {
+		t.Errorf("expected logger fields to be limited too, got %v", e.Fields["service"])
+	}
+	r := e.Fields["response"].(map[string]any)
+	if r["status"] != 200 || r["body"] != "0123...[truncated 10 bytes]" || r["Meta"] != MaxDepthMarker {
+		t.Errorf("unexpected response %v", r)
+	}
+	http := e.Fields["http"].(formatter.Group)
+	if http["method"] != "GET" || http["tls"] != MaxDepthMarker {
+		t.Errorf("unexpected http group %v", http)
+	}
+	if _, ok := e.Fields["extra"]; ok || e.Fields[FieldsDroppedKey] != int64(2) || len(e.Attrs) != 4 {
+		t.Errorf("expected two fields to be dropped to make room for the count, got %v", e.Fields)
+	}
+	if resp.Meta["a"] != 1 || string(resp.Body) != "0123456789" {
+		t.Error("expected the logged value to be left untouched")
+	}
+}
+
+func TestLimitsEntrySize(t *testing.T) {
+	mock := &mockSink{}
+	logger := New(WithSink(mock), WithCaller(false), WithLimits(Limits{MaxEntrySize: 200}))
+
+	logger.Info("small", slog.String("k", "v"))
+	logger.Info("big", slog.String("id", "42"), slog.String("body", strings.Repeat("x", 1000)))
+	logger.Info(strings.Repeat("m", 1000))
+
+	if _, ok := mock.entries[0].Fields[EntryTruncatedKey]; ok {
+		t.Error("expected a small entry to be left alone")
+	}
+	big := mock.entries[1]
+	if big.Fields["id"] != "42" || big.Fields["body"] != nil || big.Fields[EntryTruncatedKey] == nil {
+		t.Errorf("expected the last field to be dropped, got %v", big.Fields)
+	}
+	for _, e := range mock.entries {
+		if size := encodedSize(e); size > 200 {
+			t.Errorf("entry %.20q is %d bytes, over the limit", e.Message, size)
+		}
+	}
+}
+
+func TestLimitsEntrySizeUnreachable(t *testing.T) {
+	mock := &mockSink{}
+	logger := New(WithSink(mock), WithLimits(Limits{MaxEntrySize: 60}))
+
+	logger.Info("hi", slog.String("k", "v"))
+
+	e := mock.entries[0]
+	if e.Message != "hi" {
+		t.Errorf("expected a short message to be left alone, got %q", e.Message)
+	}
+	if _, ok := e.Fields["k"]; ok || e.Fields[EntryTruncatedKey] == nil {
+		t.Errorf("expected fields to be dropped, got %v", e.Fields)
+	}
+}
+
+func TestLimitsAfterHooks(t *testing.T) {
+	mock := &mockSink{}
+	logger := New(
+		WithSink(mock),
+		WithLimits(Limits{MaxStringLength: 10, MaxFields: 2}),
+		WithHook(func(_ context.Context, entry *formatter.Entry) error {
+			entry.Fields["body"] = strings.Repeat("b", 100)
+			return nil
+		}),
+	)
+
+	logger.Info("hooked", slog.String("id", "42"), slog.Any("err", errors.New(strings.Repeat("e", 50))))
+
+	e := mock.entries[0]
+	if len(e.Attrs) > 2 || len(e.Fields) > 2 {
+		t.Errorf("expected at most 2 fields, got %v", e.Fields)
+	}
+	if e.Fields["id"] != "42" || e.Fields[FieldsDroppedKey] != int64(2) {
+		t.Errorf("expected the hook's field to be dropped, got %v", e.Fields)
+	}
+
+	logger = New(WithSink(mock), WithLimits(Limits{MaxStringLength: 10}), WithHook(func(_ context.Context, entry *formatter.Entry) error {
+		entry.Fields["body"] = strings.Repeat("b", 100)
+		return nil
+	}))
+	logger.Info("hooked", slog.Any("err", errors.New(strings.Repeat("e", 50))))
+
+	e = mock.entries[1]
+	if e.Fields["body"] != "bbbbbbbbbb...[truncated 100 bytes]" {
+		t.Errorf("expected the hook's field to be truncated, got %v", e.Fields["body"])
+	}
+	if e.Fields["err"] != "eeeeeeeeee...[truncated 50 bytes]" {
+		t.Errorf("expected the error message to be truncated, got %v", e.Fields["err"])
+	}
+}
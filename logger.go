@@ -3,68 +3,56 @@ package sloggergo
 import (
 	"context"
 	"log/slog"
-	"maps"
 	"os"
 	"runtime"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/godeh/sloggergo/formatter"
+	"github.com/godeh/sloggergo/level"
 	"github.com/godeh/sloggergo/sink"
 )
 
 // Level represents the severity of a log entry.
-type Level int
+// Levels share the numeric scale of log/slog; see the level package for
+// the full mapping.
+type Level = level.Level
 
 const (
-	DebugLevel Level = iota
-	InfoLevel
-	WarnLevel
-	ErrorLevel
-	FatalLevel
+	TraceLevel    = level.Trace
+	DebugLevel    = level.Debug
+	InfoLevel     = level.Info
+	NoticeLevel   = level.Notice
+	WarnLevel     = level.Warn
+	ErrorLevel    = level.Error
+	CriticalLevel = level.Critical
+	PanicLevel    = level.Panic
+	FatalLevel    = level.Fatal
 )
 
-func (l Level) String() string {
-	switch l {
-	case DebugLevel:
-		return "DEBUG"
-	case InfoLevel:
-		return "INFO"
-	case WarnLevel:
-		return "WARN"
-	case ErrorLevel:
-		return "ERROR"
-	case FatalLevel:
-		return "FATAL"
-	default:
-		return "UNKNOWN"
-	}
+// ParseLevel parses a level name such as "debug" or "WARN", including
+// levels added with RegisterLevel. Unknown names are reported as an error.
+func ParseLevel(s string) (Level, error) {
+	return level.Parse(s)
 }
 
-// ParseLevel parses a string into a Level.
-func ParseLevel(s string) Level {
-	switch s {
-	case "debug", "DEBUG":
-		return DebugLevel
-	case "info", "INFO":
-		return InfoLevel
-	case "warn", "WARN", "warning", "WARNING":
-		return WarnLevel
-	case "error", "ERROR":
-		return ErrorLevel
-	case "fatal", "FATAL":
-		return FatalLevel
-	default:
-		return InfoLevel
-	}
+// RegisterLevel defines a custom level with the given name, severity and
+// ANSI color. Log at it with Logger.Log.
+func RegisterLevel(name string, l Level, color string) error {
+	return level.Register(name, l, color)
 }
 
 // Logger is the main logging interface.
 type Logger struct {
 	mu           sync.RWMutex
-	level        Level
-	sinks        []sink.Sink
-	fields       map[string]any
+	level        *LevelVar
+	sharedLevel  bool
+	sinks        atomic.Pointer[[]sink.Sink]
+	attrs        []slog.Attr
+	cache        *formatter.AttrCache
+	groups       []string
 	addCaller    bool
 	timeFormat   string
 	errorHandler ErrorHandler
@@ -72,18 +60,35 @@ type Logger struct {
 	// Context extraction
 	extractor ContextExtractor
 
-	// Hooks
-	hooks []Hook
+	// Hooks, replaced as a whole when one is added or removed
+	hooks atomic.Pointer[hookSet]
+
+	// Caller reporting, and the functions marked with Helper
+	callerOpts callerOptions
+	helpers    *helperSet
+
+	// Stack trace capture
+	stack stackOptions
+
+	// Per-call-site rate limits, shared with derived loggers
+	limits *rateLimits
+
+	// Entry size limits
+	sizeLimits Limits
+
+	// Exit behavior for Fatal, shared with derived loggers
+	exit *exitState
+
+	// Named loggers and per-name level overrides
+	name      string
+	names     *nameLevels
+	nameCache atomic.Pointer[nameLevelCache]
 }
 
 // ContextExtractor extracts attributes from a context.
 type ContextExtractor func(ctx context.Context) []slog.Attr
 
-// Hook is a function that can intercept and modify log entries.
-// It returns an error if the entry should be dropped or if an error occurred.
-type Hook func(ctx context.Context, entry *formatter.Entry) error
-
-// ErrorHandler is a function that handles errors from sinks.
+// ErrorHandler is a function that handles errors from sinks and hooks.
 type ErrorHandler func(error)
 
 // Option configures a Logger.
@@ -92,21 +97,33 @@ type Option func(*Logger)
 // WithLevel sets the minimum log level.
 func WithLevel(level Level) Option {
 	return func(l *Logger) {
-		l.level = level
+		l.level.Set(level)
+	}
+}
+
+// WithLevelVar makes the logger read its minimum level from v, which can be
+// shared with other loggers and changed at any time. Loggers derived from
+// this one share v as well, so SetLevel on any of them changes all of them.
+func WithLevelVar(v *LevelVar) Option {
+	return func(l *Logger) {
+		l.level = v
+		l.sharedLevel = true
 	}
 }
 
 // WithSink adds a sink to the logger.
 func WithSink(s sink.Sink) Option {
 	return func(l *Logger) {
-		l.sinks = append(l.sinks, s)
+		l.addSink(s)
 	}
 }
 
 // WithFields adds default fields to all log entries.
 func WithFields(fields map[string]any) Option {
 	return func(l *Logger) {
-		l.fields = fields
+		for _, a := range formatter.AttrsFromFields(fields) {
+			l.attrs = appendAttr(l.attrs, a)
+		}
 	}
 }
 
@@ -117,7 +134,9 @@ func WithCaller(enabled bool) Option {
 	}
 }
 
-// WithTimeFormat sets the time format for log entries.
+// WithTimeFormat sets the default time format for log entries.
+// It is a time layout or one of the formatter.Timestamp constants, and is
+// used by formatters that do not set a format of their own.
 func WithTimeFormat(format string) Option {
 	return func(l *Logger) {
 		l.timeFormat = format
@@ -131,32 +150,37 @@ func WithContextExtractor(extractor ContextExtractor) Option {
 	}
 }
 
-// WithHook adds a hook to the logger.
-func WithHook(hook Hook) Option {
+// WithErrorHandler sets the error handler for the logger.
+func WithErrorHandler(handler ErrorHandler) Option {
 	return func(l *Logger) {
-		l.hooks = append(l.hooks, hook)
+		l.errorHandler = handler
 	}
 }
 
-// WithErrorHandler sets the error handler for the logger.
-func WithErrorHandler(handler ErrorHandler) Option {
+// WithExitFunc replaces the function called by Fatal after the entry has
+// been written and the sinks closed. The default is os.Exit. Tests can use
+// it to observe Fatal without ending the process.
+func WithExitFunc(fn func(code int)) Option {
 	return func(l *Logger) {
-		l.errorHandler = handler
+		l.exit.fn = fn
 	}
 }
 
 // New creates a new logger with the given options.
 func New(opts ...Option) *Logger {
 	l := &Logger{
-		level:      InfoLevel,
-		sinks:      make([]sink.Sink, 0),
-		fields:     make(map[string]any),
+		level:      NewLevelVar(InfoLevel),
 		addCaller:  true,
 		timeFormat: time.RFC3339Nano,
+		exit:       &exitState{fn: os.Exit},
+		names:      newNameLevels(),
+		limits:     &rateLimits{},
+		helpers:    &helperSet{},
 	}
 	for _, opt := range opts {
 		opt(l)
 	}
+	l.cache = newAttrCache(l.attrs)
 	return l
 }
 
@@ -168,40 +192,111 @@ func (l *Logger) Shutdown(reason string) {
 }
 
 // With returns a new logger with additional fields.
+// keyvals are alternating keys and values, or slog.Attr values, following
+// the same rules as slog.Logger.With.
 func (l *Logger) With(keyvals ...any) *Logger {
-	fields := make(map[string]any)
+	attrs := argsToAttrs(keyvals)
 
 	l.mu.RLock()
-	maps.Copy(fields, l.fields)
+	fields := slices.Clone(l.attrs)
 	l.mu.RUnlock()
 
-	for i := 0; i < len(keyvals)-1; i += 2 {
-		if key, ok := keyvals[i].(string); ok {
-			fields[key] = keyvals[i+1]
-		}
+	for _, a := range nestAttrs(l.groups, attrs) {
+		fields = appendAttr(fields, a)
 	}
 
-	return &Logger{
-		level:      l.level,
-		sinks:      l.sinks,
-		fields:     fields,
-		addCaller:  l.addCaller,
-		timeFormat: l.timeFormat,
+	return l.child(fields, l.groups)
+}
+
+// WithGroup returns a new logger that nests all later fields, both from
+// With and from the log call itself, under the given group name.
+// Fields already on the logger stay where they are.
+func (l *Logger) WithGroup(name string) *Logger {
+	if name == "" {
+		return l
 	}
+
+	l.mu.RLock()
+	fields := l.attrs
+	l.mu.RUnlock()
+
+	return l.child(fields, append(slices.Clip(l.groups), name))
+}
+
+// child creates a derived logger with the given fields and groups.
+//
+// The child inherits everything else from l: sinks, hooks, context
+// extractor, error handler, caller and time settings, name and exit
+// handlers. It takes a snapshot of l's level, sinks and hooks, so later
+// SetLevel and AddSink calls on either logger do not affect the other,
+// unless the level was shared with WithLevelVar or NewLevelHandler.
+// Per-name levels and exit handlers are always shared.
+func (l *Logger) child(fields []slog.Attr, groups []string) *Logger {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	level := l.level
+	if !l.sharedLevel {
+		level = NewLevelVar(l.level.Level())
+	}
+
+	c := &Logger{
+		level:        level,
+		sharedLevel:  l.sharedLevel,
+		attrs:        fields,
+		cache:        newAttrCache(fields),
+		groups:       groups,
+		addCaller:    l.addCaller,
+		timeFormat:   l.timeFormat,
+		errorHandler: l.errorHandler,
+		extractor:    l.extractor,
+		callerOpts:   l.callerOpts,
+		helpers:      l.helpers,
+		stack:        l.stack,
+		exit:         l.exit,
+		name:         l.name,
+		names:        l.names,
+		limits:       l.limits,
+		sizeLimits:   l.sizeLimits,
+	}
+	c.sinks.Store(l.sinks.Load())
+	c.hooks.Store(l.hooks.Load())
+	return c
 }
 
 // SetLevel changes the minimum log level.
+// It affects loggers derived with With, WithGroup or Named only if the
+// level is shared through WithLevelVar or NewLevelHandler.
 func (l *Logger) SetLevel(level Level) {
-	l.mu.Lock()
-	defer l.mu.Unlock()
-	l.level = level
+	l.level.Set(level)
+}
+
+// GetLevel returns the minimum log level.
+func (l *Logger) GetLevel() Level {
+	return l.level.Level()
 }
 
 // AddSink adds a new sink to the logger.
+// Loggers already derived from this one do not receive it.
 func (l *Logger) AddSink(s sink.Sink) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	l.sinks = append(l.sinks, s)
+	l.addSink(s)
+}
+
+// addSink stores a copy of the sink list with s added, so that the list
+// can be read without locking.
+func (l *Logger) addSink(s sink.Sink) {
+	sinks := append(slices.Clip(l.loadSinks()), s)
+	l.sinks.Store(&sinks)
+}
+
+// loadSinks returns the current sink list, which must not be modified.
+func (l *Logger) loadSinks() []sink.Sink {
+	if sinks := l.sinks.Load(); sinks != nil {
+		return *sinks
+	}
+	return nil
 }
 
 // Close closes all sinks.
@@ -210,7 +305,7 @@ func (l *Logger) Close() error {
 	defer l.mu.Unlock()
 
 	var lastErr error
-	for _, s := range l.sinks {
+	for _, s := range l.loadSinks() {
 		if err := s.Close(); err != nil {
 			lastErr = err
 		}
@@ -220,106 +315,108 @@ func (l *Logger) Close() error {
 
 // log is the internal logging method.
 func (l *Logger) log(ctx context.Context, level Level, msg string, keyvals ...slog.Attr) {
-	l.mu.RLock()
-	if level < l.level {
-		l.mu.RUnlock()
-		return
+	if l.enabled(level) {
+		// Get caller
+		var caller runtime.Frame
+		if l.addCaller {
+			caller = l.callerFrame(3)
+		}
+
+		l.write(ctx, level, time.Now(), msg, caller, keyvals)
 	}
-	sinks := l.sinks
-	timeFormat := l.timeFormat
-	l.mu.RUnlock()
 
-	// Add context attributes if valid context and extractor is set
-	if ctx != nil && l.extractor != nil {
-		ctxAttrs := l.extractor(ctx)
-		if len(ctxAttrs) > 0 {
-			// Prepend context attributes to avoid overriding explicit keyvals?
-			// Or append? Usually specific overrides general.
-			// Let's append context attrs then keyvals.
-			newKeyvals := make([]slog.Attr, 0, len(ctxAttrs)+len(keyvals))
-			newKeyvals = append(newKeyvals, ctxAttrs...)
-			newKeyvals = append(newKeyvals, keyvals...)
-			keyvals = newKeyvals
+	// Panic and Fatal take effect even when their entry is filtered out.
+	switch level {
+	case PanicLevel:
+		panic(msg)
+	case FatalLevel:
+		l.fatalExit(1)
+	}
+}
+
+// enabled reports whether entries at the given level pass the minimum level.
+// For named loggers, a matching per-name override replaces the level.
+func (l *Logger) enabled(level Level) bool {
+	if l.name != "" {
+		if override, ok := l.nameLevel(); ok {
+			return level >= override
 		}
 	}
 
-	// Merge logger-level fields with call-site fields
-	fields := make(map[string]any)
-	l.mu.RLock()
-	maps.Copy(fields, l.fields)
-	l.mu.RUnlock()
+	return level >= l.level.Level()
+}
 
-	for _, val := range keyvals {
-		fields[val.Key] = val.Value.Any()
-	}
+// write builds an entry, runs hooks and hands it to every sink.
+// It never exits the process, whatever the level.
+func (l *Logger) write(ctx context.Context, level Level, t time.Time, msg string, caller runtime.Frame, keyvals []slog.Attr) {
+	entry := l.newEntry(ctx, level, t, msg, caller, keyvals)
+	defer releaseEntry(entry)
 
-	// Get caller
-	caller := ""
-	if l.addCaller {
-		caller = getCaller(3)
+	if !l.runHooks(ctx, level, entry) {
+		return
 	}
+	l.applyLimits(entry)
+	l.writeEntry(ctx, level, entry)
+}
 
-	// Create formatter entry
-	entry := &formatter.Entry{
-		Time:    time.Now().Format(timeFormat),
-		Level:   level.String(),
-		Message: msg,
-		Fields:  fields,
-		Caller:  caller,
-		Context: ctx,
+// newEntry builds a formatter entry from the logger's fields, the context
+// and the call-site attributes. The entry comes from a pool; callers
+// return it with releaseEntry once every sink has written it.
+func (l *Logger) newEntry(ctx context.Context, level Level, t time.Time, msg string, caller runtime.Frame, keyvals []slog.Attr) *formatter.Entry {
+	entry := entryPool.Get().(*formatter.Entry)
+	entry.Time = t
+	entry.TimeFormat = l.timeFormat
+	entry.Level = level.String()
+	entry.Message = msg
+	if caller.PC != 0 {
+		entry.Caller, entry.Source = l.callerOpts.render(caller)
 	}
+	entry.Context = ctx
 
-	// Run hooks
-	for _, hook := range l.hooks {
-		if err := hook(ctx, entry); err != nil {
-			// Hook returned error/drop signal.
-			// We stop processing this entry.
-			return
-		}
-	}
+	// Merge logger-level fields, context fields and call-site fields,
+	// in that order, so that the most specific value wins.
+	entry.Attrs = append(entry.Attrs, l.attrs...)
+	entry.Cache = l.cache
 
-	for _, s := range sinks {
-		if err := s.Write(entry); err != nil {
-			if l.errorHandler != nil {
-				l.errorHandler(err)
+	if ctx != nil {
+		for _, a := range ContextAttrs(ctx) {
+			addEntryAttr(entry, a)
+		}
+		if l.extractor != nil {
+			for _, a := range l.extractor(ctx) {
+				addEntryAttr(entry, a)
 			}
 		}
 	}
 
-	if level == FatalLevel {
-		os.Exit(1)
+	if len(l.groups) > 0 {
+		keyvals = nestAttrs(l.groups, keyvals)
 	}
-}
-
-func getCaller(skip int) string {
-	_, file, line, ok := runtime.Caller(skip)
-	if !ok {
-		return ""
+	for _, a := range keyvals {
+		addEntryAttr(entry, a)
 	}
 
-	short := file
-	for i := len(file) - 1; i > 0; i-- {
-		if file[i] == '/' {
-			short = file[i+1:]
-			break
-		}
+	if l.stack.wantStack(level) {
+		addEntryAttr(entry, slog.Any(stackKey, captureStack(l.stack.depth)))
 	}
 
-	return short + ":" + itoa(line)
+	fillFields(entry)
+	return entry
 }
 
-func itoa(i int) string {
-	if i == 0 {
-		return "0"
-	}
-	var b [20]byte
-	pos := len(b)
-	for i > 0 {
-		pos--
-		b[pos] = byte('0' + i%10)
-		i /= 10
-	}
-	return string(b[pos:])
+// Log logs a message at any level, including custom levels.
+func (l *Logger) Log(ctx context.Context, level Level, msg string, keyvals ...slog.Attr) {
+	l.log(ctx, level, msg, keyvals...)
+}
+
+// Trace logs a trace message.
+func (l *Logger) Trace(msg string, keyvals ...slog.Attr) {
+	l.log(context.Background(), TraceLevel, msg, keyvals...)
+}
+
+// TraceContext logs a trace message with context.
+func (l *Logger) TraceContext(ctx context.Context, msg string, keyvals ...slog.Attr) {
+	l.log(ctx, TraceLevel, msg, keyvals...)
 }
 
 // Debug logs a debug message.
@@ -342,6 +439,16 @@ func (l *Logger) InfoContext(ctx context.Context, msg string, keyvals ...slog.At
 	l.log(ctx, InfoLevel, msg, keyvals...)
 }
 
+// Notice logs a notice message.
+func (l *Logger) Notice(msg string, keyvals ...slog.Attr) {
+	l.log(context.Background(), NoticeLevel, msg, keyvals...)
+}
+
+// NoticeContext logs a notice message with context.
+func (l *Logger) NoticeContext(ctx context.Context, msg string, keyvals ...slog.Attr) {
+	l.log(ctx, NoticeLevel, msg, keyvals...)
+}
+
 // Warn logs a warning message.
 func (l *Logger) Warn(msg string, keyvals ...slog.Attr) {
 	l.log(context.Background(), WarnLevel, msg, keyvals...)
@@ -362,7 +469,28 @@ func (l *Logger) ErrorContext(ctx context.Context, msg string, keyvals ...slog.A
 	l.log(ctx, ErrorLevel, msg, keyvals...)
 }
 
-// Fatal logs a fatal message and exits.
+// Critical logs a critical message.
+func (l *Logger) Critical(msg string, keyvals ...slog.Attr) {
+	l.log(context.Background(), CriticalLevel, msg, keyvals...)
+}
+
+// CriticalContext logs a critical message with context.
+func (l *Logger) CriticalContext(ctx context.Context, msg string, keyvals ...slog.Attr) {
+	l.log(ctx, CriticalLevel, msg, keyvals...)
+}
+
+// Panic logs a panic message and then panics with the message.
+func (l *Logger) Panic(msg string, keyvals ...slog.Attr) {
+	l.log(context.Background(), PanicLevel, msg, keyvals...)
+}
+
+// PanicContext logs a panic message with context and then panics.
+func (l *Logger) PanicContext(ctx context.Context, msg string, keyvals ...slog.Attr) {
+	l.log(ctx, PanicLevel, msg, keyvals...)
+}
+
+// Fatal logs a fatal message, runs the exit handlers, syncs and closes
+// every sink, and exits with status 1.
 func (l *Logger) Fatal(msg string, keyvals ...slog.Attr) {
 	l.log(context.Background(), FatalLevel, msg, keyvals...)
 }
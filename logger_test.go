@@ -1,13 +1,18 @@
 package sloggergo
 
 import (
+	"bytes"
+	"context"
 	"log/slog"
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/godeh/sloggergo/formatter"
+	"github.com/godeh/sloggergo/level"
 	"github.com/godeh/sloggergo/sink"
 )
 
@@ -20,7 +25,7 @@ type mockSink struct {
 func (m *mockSink) Write(entry *formatter.Entry) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.entries = append(m.entries, entry)
+	m.entries = append(m.entries, entry.Clone())
 	return nil
 }
 
@@ -135,21 +140,29 @@ func TestLevelParsing(t *testing.T) {
 	tests := []struct {
 		input    string
 		expected Level
+		wantErr  bool
 	}{
-		{"debug", DebugLevel},
-		{"DEBUG", DebugLevel},
-		{"info", InfoLevel},
-		{"INFO", InfoLevel},
-		{"warn", WarnLevel},
-		{"warning", WarnLevel},
-		{"error", ErrorLevel},
-		{"fatal", FatalLevel},
-		{"unknown", InfoLevel},
+		{"trace", TraceLevel, false},
+		{"debug", DebugLevel, false},
+		{"DEBUG", DebugLevel, false},
+		{"info", InfoLevel, false},
+		{"INFO", InfoLevel, false},
+		{"notice", NoticeLevel, false},
+		{"warn", WarnLevel, false},
+		{"warning", WarnLevel, false},
+		{"error", ErrorLevel, false},
+		{"critical", CriticalLevel, false},
+		{"fatal", FatalLevel, false},
+		{"INFO+1", InfoLevel + 1, false},
+		{"unknown", InfoLevel, true},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.input, func(t *testing.T) {
-			level := ParseLevel(tt.input)
+			level, err := ParseLevel(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLevel(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
 			if level != tt.expected {
 				t.Errorf("ParseLevel(%q) = %v, want %v", tt.input, level, tt.expected)
 			}
@@ -167,6 +180,10 @@ func TestLevelString(t *testing.T) {
 		{WarnLevel, "WARN"},
 		{ErrorLevel, "ERROR"},
 		{FatalLevel, "FATAL"},
+		{TraceLevel, "TRACE"},
+		{NoticeLevel, "NOTICE"},
+		{CriticalLevel, "CRITICAL"},
+		{InfoLevel + 1, "INFO+1"},
 	}
 
 	for _, tt := range tests {
@@ -246,7 +263,7 @@ func TestCallerInfo(t *testing.T) {
 func TestStdoutSink(t *testing.T) {
 	s := sink.NewStdout()
 	entry := &formatter.Entry{
-		Time:    "2024-01-01T00:00:00Z",
+		Time:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
 		Level:   "INFO",
 		Message: "test",
 	}
@@ -257,6 +274,172 @@ func TestStdoutSink(t *testing.T) {
 	_ = s.Close()
 }
 
+func TestSlogSink(t *testing.T) {
+	var buf bytes.Buffer
+	s := sink.NewSlog(slog.NewJSONHandler(&buf, nil))
+	log := New(WithSink(s), WithCaller(false))
+
+	log.Warn("slow query", slog.Int("ms", 250))
+
+	out := buf.String()
+	for _, want := range []string{`"level":"WARN"`, `"msg":"slow query"`, `"ms":250`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %s, got %s", want, out)
+		}
+	}
+}
+
+func TestLoggerFieldCache(t *testing.T) {
+	tests := []struct {
+		name string
+		f    formatter.Formatter
+		want []string
+	}{
+		{"json", formatter.NewJSON(), []string{
+			`"fields":{"service":"api","region":"eu"}}`,
+			`"fields":{"service":"api","region":"us"}}`,
+			`"fields":{"service":"api","region":"eu","n":1}}`,
+		}},
+		{"text", formatter.NewTextNoColor(), []string{
+			"a service=api region=eu",
+			"b service=api region=us",
+			"c service=api region=eu n=1",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := New(WithSink(sink.NewStdout(sink.WithWriter(&buf), sink.WithFormatter(tt.f))), WithCaller(false)).
+				With("service", "api", "region", "eu")
+
+			log.Info("a")
+			log.Info("b", slog.String("region", "us"))
+			log.Info("c", slog.Int("n", 1))
+
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			if len(lines) != len(tt.want) {
+				t.Fatalf("expected %d lines, got %q", len(tt.want), buf.String())
+			}
+			for i, want := range tt.want {
+				if !strings.HasSuffix(lines[i], want) {
+					t.Errorf("line %d: expected suffix %s, got %s", i, want, lines[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFilterSink(t *testing.T) {
+	all := &mockSink{}
+	errs := &mockSink{}
+	audit := &mockSink{}
+	log := New(
+		WithLevel(DebugLevel),
+		WithSink(all),
+		WithSink(sink.NewFilter(errs, sink.WithMinLevel(ErrorLevel))),
+		WithSink(sink.NewFilter(audit, sink.WithPredicate(func(e *formatter.Entry) bool {
+			return e.Fields["audit"] == true
+		}))),
+	)
+
+	log.Debug("debug")
+	log.Info("login", slog.Bool("audit", true))
+	log.Error("failed")
+
+	if all.Len() != 3 {
+		t.Errorf("expected 3 entries in unfiltered sink, got %d", all.Len())
+	}
+	if errs.Len() != 1 || errs.entries[0].Message != "failed" {
+		t.Errorf("expected only the error entry, got %d entries", errs.Len())
+	}
+	if audit.Len() != 1 || audit.entries[0].Message != "login" {
+		t.Errorf("expected only the audit entry, got %d entries", audit.Len())
+	}
+}
+
+func TestDedupSink(t *testing.T) {
+	mock := &mockSink{}
+	dedup := sink.NewDedup(mock, time.Hour, sink.WithDedupFields("host"))
+	log := New(WithSink(dedup), WithCaller(false))
+
+	for i := 0; i < 5; i++ {
+		log.Error("connection refused", slog.String("host", "db1"))
+	}
+	log.Error("connection refused", slog.String("host", "db2"))
+	log.Warn("connection refused", slog.String("host", "db1"))
+
+	if mock.Len() != 3 {
+		t.Fatalf("expected 3 distinct entries to pass through, got %d", mock.Len())
+	}
+
+	if err := dedup.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if mock.Len() != 4 {
+		t.Fatalf("expected one summary entry, got %d entries", mock.Len())
+	}
+	summary := mock.entries[3]
+	if summary.Message != "connection refused" || summary.Fields["host"] != "db1" {
+		t.Errorf("unexpected summary entry %+v", summary)
+	}
+	if summary.Fields[sink.RepeatCountKey] != int64(4) {
+		t.Errorf("expected 4 repeats, got %v", summary.Fields[sink.RepeatCountKey])
+	}
+	first, _ := summary.Fields[sink.FirstSeenKey].(time.Time)
+	last, _ := summary.Fields[sink.LastSeenKey].(time.Time)
+	if first.IsZero() || last.Before(first) {
+		t.Errorf("unexpected first_seen %v and last_seen %v", first, last)
+	}
+
+	log.Error("connection refused", slog.String("host", "db1"))
+	if mock.Len() != 5 {
+		t.Errorf("expected a new window after the summary, got %d entries", mock.Len())
+	}
+}
+
+// slowSummarySink takes a while to write dedup summaries and records
+// whether one was written after it was closed.
+type slowSummarySink struct {
+	mockSink
+	started     chan struct{}
+	closed      atomic.Bool
+	afterClosed atomic.Bool
+}
+
+func (s *slowSummarySink) Write(entry *formatter.Entry) error {
+	if _, ok := entry.Fields[sink.RepeatCountKey]; ok {
+		close(s.started)
+		time.Sleep(50 * time.Millisecond)
+		if s.closed.Load() {
+			s.afterClosed.Store(true)
+		}
+	}
+	return s.mockSink.Write(entry)
+}
+
+func (s *slowSummarySink) Close() error {
+	s.closed.Store(true)
+	return nil
+}
+
+func TestDedupCloseWaitsForSummary(t *testing.T) {
+	s := &slowSummarySink{started: make(chan struct{})}
+	dedup := sink.NewDedup(s, 10*time.Millisecond)
+	log := New(WithSink(dedup), WithCaller(false))
+
+	log.Error("connection refused")
+	log.Error("connection refused")
+	<-s.started // the window expired and its summary is being written
+
+	if err := dedup.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if s.afterClosed.Load() || s.Len() != 2 {
+		t.Errorf("expected the summary to be written before the sink was closed, got %d entries", s.Len())
+	}
+}
+
 func TestNewFromConfig(t *testing.T) {
 	// Create temporary config file
 	configContent := `{
@@ -285,3 +468,289 @@ func TestNewFromConfig(t *testing.T) {
 
 	log.Info("test from config")
 }
+
+func TestLoggerGroups(t *testing.T) {
+	mock := &mockSink{}
+	log := New(WithSink(mock)).With("app", "api").WithGroup("http").With("method", "GET")
+
+	log.Info("request", slog.Int("status", 200), slog.Group("timing", slog.Int("ms", 12)))
+
+	if mock.Len() != 1 {
+		t.Fatalf("expected 1 entry, got %d", mock.Len())
+	}
+	fields := mock.entries[0].Fields
+	if fields["app"] != "api" {
+		t.Errorf("expected app=api at top level, got %v", fields["app"])
+	}
+	http, ok := fields["http"].(formatter.Group)
+	if !ok {
+		t.Fatalf("expected http group, got %T", fields["http"])
+	}
+	if http["method"] != "GET" || http["status"] != int64(200) {
+		t.Errorf("expected method and status in http group, got %v", http)
+	}
+	if timing, ok := http["timing"].(formatter.Group); !ok || timing["ms"] != int64(12) {
+		t.Errorf("expected nested timing group, got %v", http["timing"])
+	}
+}
+
+func TestFormatGroups(t *testing.T) {
+	entry := &formatter.Entry{
+		Level:   "INFO",
+		Message: "request",
+		Fields: map[string]any{
+			"http": formatter.Group{"status": int64(200)},
+		},
+	}
+
+	text, err := formatter.NewTextNoColor().Format(entry)
+	if err != nil {
+		t.Fatalf("Format() returned error: %v", err)
+	}
+	if !strings.Contains(string(text), "http.status=200") {
+		t.Errorf("expected dotted key in text output, got %q", text)
+	}
+
+	data, err := formatter.NewJSON().Format(entry)
+	if err != nil {
+		t.Fatalf("Format() returned error: %v", err)
+	}
+	if !strings.Contains(string(data), `"http":{"status":200}`) {
+		t.Errorf("expected nested object in JSON output, got %s", data)
+	}
+}
+
+type testUser struct {
+	ID    int
+	Email string
+}
+
+func (u testUser) LogValue() slog.Value {
+	return slog.GroupValue(slog.Int("id", u.ID), slog.String("email", "[REDACTED]"))
+}
+
+type testOrder struct {
+	Owner testUser
+}
+
+func (o testOrder) LogValue() slog.Value {
+	return slog.GroupValue(slog.Any("owner", o.Owner))
+}
+
+func TestLoggerResolvesLogValuer(t *testing.T) {
+	mock := &mockSink{}
+	log := New(WithSink(mock))
+
+	log.Info("order placed", slog.Any("order", testOrder{Owner: testUser{ID: 7, Email: "a@b.c"}}))
+
+	order, ok := mock.entries[0].Fields["order"].(formatter.Group)
+	if !ok {
+		t.Fatalf("expected order to resolve to a group, got %T", mock.entries[0].Fields["order"])
+	}
+	owner, ok := order["owner"].(formatter.Group)
+	if !ok {
+		t.Fatalf("expected nested owner to resolve to a group, got %T", order["owner"])
+	}
+	if owner["email"] != "[REDACTED]" {
+		t.Errorf("expected redacted email, got %v", owner["email"])
+	}
+}
+
+func TestFormatDurations(t *testing.T) {
+	entry := &formatter.Entry{
+		Level:   "INFO",
+		Message: "done",
+		Fields:  map[string]any{"elapsed": 1500 * time.Millisecond},
+	}
+
+	text, _ := formatter.NewTextNoColor().Format(entry)
+	if !strings.Contains(string(text), "elapsed=1.5s") {
+		t.Errorf("expected duration in text output, got %q", text)
+	}
+	data, _ := formatter.NewJSON().Format(entry)
+	if !strings.Contains(string(data), `"elapsed":"1.5s"`) {
+		t.Errorf("expected duration in JSON output, got %s", data)
+	}
+}
+
+func TestLoggerFieldOrder(t *testing.T) {
+	var buf bytes.Buffer
+	log := New(
+		WithSink(sink.NewStdout(sink.WithWriter(&buf), sink.WithFormatter(formatter.NewTextNoColor()))),
+		WithCaller(false),
+		WithFields(map[string]any{"service": "api", "env": "prod"}),
+		WithContextExtractor(func(ctx context.Context) []slog.Attr {
+			return []slog.Attr{slog.String("trace_id", "t-1"), slog.String("env", "ctx")}
+		}),
+	)
+
+	log.InfoContext(context.Background(), "ordered", slog.Int("zeta", 1), slog.Int("alpha", 2), slog.String("service", "worker"))
+
+	out := buf.String()
+	want := "ordered env=ctx service=worker trace_id=t-1 zeta=1 alpha=2\n"
+	if !strings.HasSuffix(out, want) {
+		t.Errorf("expected output to end with %q, got %q", want, out)
+	}
+}
+
+func TestHookFieldChangesReachAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	log := New(
+		WithSink(sink.NewStdout(sink.WithWriter(&buf), sink.WithFormatter(formatter.NewTextNoColor()))),
+		WithCaller(false),
+		WithHook(func(ctx context.Context, entry *formatter.Entry) error {
+			entry.Fields["email"] = "j***@example.com"
+			delete(entry.Fields, "password")
+			entry.Fields["masked"] = true
+			return nil
+		}),
+	)
+
+	log.Info("login", slog.String("email", "john@example.com"), slog.String("password", "secret"), slog.Int("attempt", 1))
+
+	want := "login email=j***@example.com attempt=1 masked=true\n"
+	if !strings.HasSuffix(buf.String(), want) {
+		t.Errorf("expected output to end with %q, got %q", want, buf.String())
+	}
+}
+
+func TestHookGroupChangesKeepOrder(t *testing.T) {
+	var buf bytes.Buffer
+	log := New(
+		WithSink(sink.NewStdout(sink.WithWriter(&buf), sink.WithFormatter(formatter.NewJSON()))),
+		WithCaller(false),
+		WithHook(func(ctx context.Context, entry *formatter.Entry) error {
+			user := entry.Fields["user"].(formatter.Group)
+			user["password"] = "***"
+			user["role"] = "admin"
+			return nil
+		}),
+	)
+
+	log.Info("login", slog.Group("user", slog.String("zname", "ann"), slog.String("password", "secret"), slog.Int("age", 30)))
+
+	want := `"user":{"zname":"ann","password":"***","age":30,"role":"admin"}`
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("expected %s in %s", want, buf.String())
+	}
+}
+
+func TestFormatTimestamps(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("CET", 3600))
+	entry := &formatter.Entry{Time: ts, TimeFormat: time.Kitchen, Level: "INFO", Message: "tick"}
+
+	text, _ := formatter.NewTextNoColor().Format(entry)
+	if !strings.HasPrefix(string(text), "3:04AM ") {
+		t.Errorf("expected logger default layout, got %q", text)
+	}
+
+	utc := &formatter.TextFormatter{DisableColors: true, TimestampFormat: time.DateTime, UTC: true}
+	text, _ = utc.Format(entry)
+	if !strings.HasPrefix(string(text), "2024-01-02 02:04:05 ") {
+		t.Errorf("expected UTC timestamp, got %q", text)
+	}
+
+	unix := &formatter.JSONFormatter{TimestampFormat: formatter.TimestampUnixMilli}
+	data, _ := unix.Format(entry)
+	if !strings.Contains(string(data), `"time":1704161045000`) {
+		t.Errorf("expected Unix millis, got %s", data)
+	}
+}
+
+func TestCustomLevel(t *testing.T) {
+	audit := Level(6)
+	if err := RegisterLevel("audit", audit, level.ColorBlue); err != nil {
+		t.Fatalf("RegisterLevel() returned error: %v", err)
+	}
+	if err := RegisterLevel("other", audit, level.ColorBlue); err == nil {
+		t.Error("expected error when reusing a registered severity")
+	}
+
+	mock := &mockSink{}
+	log := New(WithLevel(WarnLevel), WithSink(mock))
+	log.Log(context.Background(), audit, "user deleted")
+	log.Notice("below threshold")
+
+	if mock.Len() != 1 {
+		t.Fatalf("expected 1 entry, got %d", mock.Len())
+	}
+	if mock.entries[0].Level != "AUDIT" {
+		t.Errorf("expected level AUDIT, got %s", mock.entries[0].Level)
+	}
+	if parsed, err := ParseLevel("Audit"); err != nil || parsed != audit {
+		t.Errorf("ParseLevel(Audit) = %v, %v", parsed, err)
+	}
+	if audit.Slog() != slog.Level(6) {
+		t.Errorf("expected slog level 6, got %v", audit.Slog())
+	}
+}
+
+func TestChildLoggerInheritsEverything(t *testing.T) {
+	mock := &mockSink{}
+	var sinkErrs int
+	log := New(
+		WithSink(mock),
+		WithSink(&failingSink{}),
+		WithErrorHandler(func(error) { sinkErrs++ }),
+		WithContextExtractor(func(ctx context.Context) []slog.Attr {
+			return []slog.Attr{slog.String("trace_id", "t-1")}
+		}),
+		WithHook(func(ctx context.Context, entry *formatter.Entry) error {
+			entry.Fields["email"] = "***"
+			return nil
+		}),
+	)
+
+	child := log.With("request_id", "r-1").Named("api")
+	child.InfoContext(context.Background(), "handled", slog.String("email", "a@b.c"))
+
+	if mock.Len() != 1 {
+		t.Fatalf("expected 1 entry, got %d", mock.Len())
+	}
+	fields := mock.entries[0].Fields
+	if fields["trace_id"] != "t-1" {
+		t.Errorf("expected extractor to run on child, got %v", fields)
+	}
+	if fields["email"] != "***" {
+		t.Errorf("expected hook to run on child, got %v", fields)
+	}
+	if sinkErrs != 1 {
+		t.Errorf("expected error handler to run on child, got %d calls", sinkErrs)
+	}
+}
+
+func TestChildLoggerIsolation(t *testing.T) {
+	parentSink, childSink := &mockSink{}, &mockSink{}
+	parent := New(WithSink(parentSink))
+	child := parent.With("k", "v")
+
+	parent.SetLevel(ErrorLevel)
+	child.AddSink(childSink)
+	child.Info("child only")
+	parent.Info("filtered")
+
+	if parentSink.Len() != 1 || childSink.Len() != 1 {
+		t.Errorf("expected child to keep its own level and sinks, got parent=%d child=%d", parentSink.Len(), childSink.Len())
+	}
+}
+
+func TestSharedLevelVar(t *testing.T) {
+	mock := &mockSink{}
+	lv := NewLevelVar(InfoLevel)
+	parent := New(WithSink(mock), WithLevelVar(lv))
+	child := parent.With("k", "v")
+
+	parent.SetLevel(DebugLevel)
+	child.Debug("visible")
+	lv.Set(ErrorLevel)
+	child.Warn("hidden")
+
+	if mock.Len() != 1 {
+		t.Errorf("expected shared level to reach the child, got %d entries", mock.Len())
+	}
+}
+
+type failingSink struct{}
+
+func (failingSink) Write(*formatter.Entry) error { return os.ErrClosed }
+func (failingSink) Close() error                 { return nil }
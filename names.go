@@ -0,0 +1,143 @@
+package sloggergo
+
+import (
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// nameKey is the field that holds the name of a named logger.
+const nameKey = "logger"
+
+// nameLevels holds per-name level overrides, shared by a logger and every
+// logger derived from it. An override applies to a name and to all names
+// below it, so "db" also covers "db.pool"; the longest match wins.
+type nameLevels struct {
+	mu        sync.RWMutex
+	overrides map[string]Level
+
+	// gen changes on every update so loggers can cache their lookup.
+	gen atomic.Uint64
+}
+
+func newNameLevels() *nameLevels {
+	return &nameLevels{overrides: make(map[string]Level)}
+}
+
+func (n *nameLevels) set(name string, level Level) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.overrides[name] = level
+	n.gen.Add(1)
+}
+
+func (n *nameLevels) remove(name string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	delete(n.overrides, name)
+	n.gen.Add(1)
+}
+
+func (n *nameLevels) snapshot() map[string]Level {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return maps.Clone(n.overrides)
+}
+
+// lookup returns the override for name or for its closest parent.
+func (n *nameLevels) lookup(name string) (Level, bool) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	for {
+		if level, ok := n.overrides[name]; ok {
+			return level, true
+		}
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			return 0, false
+		}
+		name = name[:i]
+	}
+}
+
+// nameLevelCache remembers a logger's override lookup for one generation
+// of nameLevels, so the level check stays a couple of atomic loads.
+type nameLevelCache struct {
+	gen   uint64
+	level Level
+	found bool
+}
+
+// nameLevel returns the override that applies to the logger's name.
+func (l *Logger) nameLevel() (Level, bool) {
+	gen := l.names.gen.Load()
+	if c := l.nameCache.Load(); c != nil && c.gen == gen {
+		return c.level, c.found
+	}
+	level, found := l.names.lookup(l.name)
+	l.nameCache.Store(&nameLevelCache{gen: gen, level: level, found: found})
+	return level, found
+}
+
+// WithName sets the name of the logger. See Logger.Named.
+func WithName(name string) Option {
+	return func(l *Logger) {
+		l.name = name
+		l.attrs = appendAttr(slices.Clone(l.attrs), slog.String(nameKey, name))
+	}
+}
+
+// WithNamedLevel sets the minimum level for loggers with the given name
+// and the names below it. See Logger.SetNamedLevel.
+func WithNamedLevel(name string, level Level) Option {
+	return func(l *Logger) {
+		l.names.set(name, level)
+	}
+}
+
+// Named returns a child logger for a subsystem. Names nest with dots, so
+// logger.Named("db").Named("pool") is named "db.pool", and the name is
+// added to every entry as the "logger" field.
+func (l *Logger) Named(name string) *Logger {
+	if name == "" {
+		return l
+	}
+	if l.name != "" {
+		name = l.name + "." + name
+	}
+
+	l.mu.RLock()
+	attrs := slices.Clone(l.attrs)
+	l.mu.RUnlock()
+
+	child := l.child(appendAttr(attrs, slog.String(nameKey, name)), l.groups)
+	child.name = name
+	return child
+}
+
+// Name returns the logger's name, or "" for an unnamed logger.
+func (l *Logger) Name() string {
+	return l.name
+}
+
+// SetNamedLevel sets the minimum level for loggers with the given name and
+// the names below it, for example "db" for both "db" and "db.pool". It
+// takes precedence over the level of those loggers and can be changed at
+// any time. Overrides are shared by all loggers derived from the same root.
+func (l *Logger) SetNamedLevel(name string, level Level) {
+	l.names.set(name, level)
+}
+
+// ResetNamedLevel removes the override set for name.
+func (l *Logger) ResetNamedLevel(name string) {
+	l.names.remove(name)
+}
+
+// NamedLevels returns a copy of the per-name level overrides.
+func (l *Logger) NamedLevels() map[string]Level {
+	return l.names.snapshot()
+}
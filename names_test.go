@@ -0,0 +1,72 @@
+package sloggergo
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/godeh/sloggergo/config"
+)
+
+func TestNamedLogger(t *testing.T) {
+	mock := &mockSink{}
+	log := New(WithSink(mock)).Named("db").Named("pool")
+
+	log.Info("connected")
+
+	if log.Name() != "db.pool" {
+		t.Errorf("expected name db.pool, got %q", log.Name())
+	}
+	if got := mock.entries[0].Fields["logger"]; got != "db.pool" {
+		t.Errorf("expected logger=db.pool, got %v", got)
+	}
+}
+
+func TestNamedLevels(t *testing.T) {
+	mock := &mockSink{}
+	root := New(WithLevel(InfoLevel), WithSink(mock), WithNamedLevel("http", WarnLevel))
+	db := root.Named("db")
+	pool := db.Named("pool")
+	http := root.Named("http")
+
+	pool.Debug("hidden")
+	http.Info("hidden")
+
+	root.SetNamedLevel("db", DebugLevel)
+	pool.Debug("visible")
+	db.Debug("visible")
+	root.Debug("hidden")
+	http.Warn("visible")
+
+	root.ResetNamedLevel("db")
+	pool.Debug("hidden")
+
+	if mock.Len() != 3 {
+		t.Fatalf("expected 3 entries, got %d", mock.Len())
+	}
+}
+
+func TestNamedLevelsFromConfig(t *testing.T) {
+	cfg := config.Config{Logger: config.LoggerConfig{
+		Level:  "info",
+		Format: "text",
+		Name:   "app",
+		Levels: map[string]string{"app.db": "debug"},
+	}}
+	data, _ := json.Marshal(cfg)
+	path := t.TempDir() + "/config.json"
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("Failed to create config file: %v", err)
+	}
+
+	log, err := NewFromConfig(path)
+	if err != nil {
+		t.Fatalf("NewFromConfig() returned error: %v", err)
+	}
+	if !log.Named("db").enabled(DebugLevel) {
+		t.Error("expected debug to be enabled for app.db")
+	}
+	if log.enabled(DebugLevel) {
+		t.Error("expected debug to be disabled for app")
+	}
+}
@@ -0,0 +1,236 @@
+package sloggergo
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"slices"
+	"sync"
+	"time"
+)
+
+// suppressedKey is the field that counts the entries a RateLimited logger
+// dropped since its previous entry.
+const suppressedKey = "suppressed"
+
+// RateLimited logs through a Logger at most as often as its policy allows.
+// Limits are tracked per call site, or per key if one is set, and shared
+// by every logger derived from the same root. Entries that are dropped are
+// counted, and the next entry that gets through carries the count in a
+// "suppressed" field. State is kept for up to 10000 call sites and keys;
+// beyond that the least recently used limits are reset, so keys should
+// come from a bounded set.
+//
+//	for _, item := range items {
+//		log.Every(5*time.Second).Warn("slow item", slog.String("id", item.ID))
+//	}
+type RateLimited struct {
+	logger *Logger
+	policy limitPolicy
+	key    string
+}
+
+// limitPolicy describes how often a RateLimited logger lets entries through.
+type limitPolicy struct {
+	every time.Duration
+	n     int
+	once  bool
+}
+
+// Every returns a logger that writes at most one entry per interval d
+// from each call site.
+func (l *Logger) Every(d time.Duration) RateLimited {
+	return RateLimited{logger: l, policy: limitPolicy{every: d}}
+}
+
+// EveryN returns a logger that writes the first entry from each call site
+// and then every n-th one.
+func (l *Logger) EveryN(n int) RateLimited {
+	return RateLimited{logger: l, policy: limitPolicy{n: n}}
+}
+
+// Once returns a logger that writes a single entry for key, from whichever
+// call site comes first. An empty key means once per call site.
+func (l *Logger) Once(key string) RateLimited {
+	return RateLimited{logger: l, policy: limitPolicy{once: true}, key: key}
+}
+
+// WithKey returns a copy of r that shares its limit with every call using
+// the same key, rather than limiting each call site separately.
+func (r RateLimited) WithKey(key string) RateLimited {
+	r.key = key
+	return r
+}
+
+// maxLimitStates bounds the number of limits a logger tree keeps state
+// for, so that keys built from request data cannot grow it forever.
+const maxLimitStates = 10000
+
+// rateLimits holds the state of every limit used by a logger tree.
+// Once more than maxLimitStates limits are in use, the state of the least
+// recently used ones is dropped, so a key that comes back after a long
+// while may get through again, even with Once.
+type rateLimits struct {
+	mu     sync.Mutex
+	states map[limitKey]*limitState
+}
+
+type limitKey struct {
+	site   uintptr
+	key    string
+	policy limitPolicy
+}
+
+type limitState struct {
+	last       time.Time
+	used       time.Time
+	seen       int
+	suppressed int64
+}
+
+// allow reports whether an entry at level may be written and returns its
+// attributes, with the suppressed count added if entries were dropped.
+// It must be called directly by the public logging method, whose caller
+// is the call site.
+func (r RateLimited) allow(level Level, keyvals []slog.Attr) (bool, []slog.Attr) {
+	if !r.logger.enabled(level) {
+		return false, nil
+	}
+
+	k := limitKey{key: r.key, policy: r.policy}
+	if k.key == "" {
+		var pc [1]uintptr
+		runtime.Callers(3, pc[:])
+		k.site = pc[0]
+	}
+
+	suppressed, ok := r.logger.limits.take(k, time.Now())
+	if !ok {
+		return false, nil
+	}
+	if suppressed > 0 {
+		keyvals = append(slices.Clip(keyvals), slog.Int64(suppressedKey, suppressed))
+	}
+	return true, keyvals
+}
+
+// take records an attempt to log under k and reports whether it is allowed,
+// along with the number of attempts dropped since the last allowed one.
+func (rl *rateLimits) take(k limitKey, now time.Time) (suppressed int64, ok bool) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if rl.states == nil {
+		rl.states = make(map[limitKey]*limitState)
+	}
+	st := rl.states[k]
+	if st == nil {
+		if len(rl.states) >= maxLimitStates {
+			rl.prune(now)
+		}
+		st = &limitState{}
+		rl.states[k] = st
+	}
+
+	st.used = now
+	st.seen++
+	switch p := k.policy; {
+	case p.once:
+		ok = st.seen == 1
+	case p.n > 0:
+		ok = (st.seen-1)%p.n == 0
+	default:
+		ok = st.last.IsZero() || now.Sub(st.last) >= p.every
+	}
+
+	if !ok {
+		st.suppressed++
+		return 0, false
+	}
+	st.last = now
+	suppressed, st.suppressed = st.suppressed, 0
+	return suppressed, true
+}
+
+// prune makes room for new limits. It drops Every limits whose interval
+// has passed with nothing suppressed, as they would let the next entry
+// through anyway, and then the least recently used limits until the map
+// is down to three quarters of maxLimitStates.
+func (rl *rateLimits) prune(now time.Time) {
+	for k, st := range rl.states {
+		if p := k.policy; !p.once && p.n <= 0 && st.suppressed == 0 && now.Sub(st.last) >= p.every {
+			delete(rl.states, k)
+		}
+	}
+
+	excess := len(rl.states) - maxLimitStates*3/4
+	if excess <= 0 {
+		return
+	}
+	keys := make([]limitKey, 0, len(rl.states))
+	for k := range rl.states {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b limitKey) int {
+		return rl.states[a].used.Compare(rl.states[b].used)
+	})
+	for _, k := range keys[:excess] {
+		delete(rl.states, k)
+	}
+}
+
+// Log logs at any level, if the limit allows it.
+func (r RateLimited) Log(ctx context.Context, level Level, msg string, keyvals ...slog.Attr) {
+	if ok, attrs := r.allow(level, keyvals); ok {
+		r.logger.log(ctx, level, msg, attrs...)
+	}
+}
+
+// Trace logs a trace message, if the limit allows it.
+func (r RateLimited) Trace(msg string, keyvals ...slog.Attr) {
+	if ok, attrs := r.allow(TraceLevel, keyvals); ok {
+		r.logger.log(context.Background(), TraceLevel, msg, attrs...)
+	}
+}
+
+// Debug logs a debug message, if the limit allows it.
+func (r RateLimited) Debug(msg string, keyvals ...slog.Attr) {
+	if ok, attrs := r.allow(DebugLevel, keyvals); ok {
+		r.logger.log(context.Background(), DebugLevel, msg, attrs...)
+	}
+}
+
+// Info logs an info message, if the limit allows it.
+func (r RateLimited) Info(msg string, keyvals ...slog.Attr) {
+	if ok, attrs := r.allow(InfoLevel, keyvals); ok {
+		r.logger.log(context.Background(), InfoLevel, msg, attrs...)
+	}
+}
+
+// Notice logs a notice message, if the limit allows it.
+func (r RateLimited) Notice(msg string, keyvals ...slog.Attr) {
+	if ok, attrs := r.allow(NoticeLevel, keyvals); ok {
+		r.logger.log(context.Background(), NoticeLevel, msg, attrs...)
+	}
+}
+
+// Warn logs a warning message, if the limit allows it.
+func (r RateLimited) Warn(msg string, keyvals ...slog.Attr) {
+	if ok, attrs := r.allow(WarnLevel, keyvals); ok {
+		r.logger.log(context.Background(), WarnLevel, msg, attrs...)
+	}
+}
+
+// Error logs an error message, if the limit allows it.
+func (r RateLimited) Error(msg string, keyvals ...slog.Attr) {
+	if ok, attrs := r.allow(ErrorLevel, keyvals); ok {
+		r.logger.log(context.Background(), ErrorLevel, msg, attrs...)
+	}
+}
+
+// Critical logs a critical message, if the limit allows it.
+func (r RateLimited) Critical(msg string, keyvals ...slog.Attr) {
+	if ok, attrs := r.allow(CriticalLevel, keyvals); ok {
+		r.logger.log(context.Background(), CriticalLevel, msg, attrs...)
+	}
+}
@@ -0,0 +1,85 @@
+package sloggergo
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRateLimitedEveryN(t *testing.T) {
+	mock := &mockSink{}
+	log := New(WithSink(mock))
+
+	for i := 0; i < 7; i++ {
+		log.EveryN(3).Info("tick")
+	}
+
+	if mock.Len() != 3 {
+		t.Fatalf("expected entries 1, 4 and 7, got %d", mock.Len())
+	}
+	if _, ok := mock.entries[0].Fields[suppressedKey]; ok {
+		t.Error("expected no suppressed count on the first entry")
+	}
+	if got := mock.entries[1].Fields[suppressedKey]; got != int64(2) {
+		t.Errorf("expected 2 suppressed entries, got %v", got)
+	}
+	if !strings.HasPrefix(mock.entries[2].Caller, "ratelimit_test.go:") {
+		t.Errorf("expected the call site as caller, got %s", mock.entries[2].Caller)
+	}
+}
+
+func TestRateLimitedEvery(t *testing.T) {
+	mock := &mockSink{}
+	log := New(WithSink(mock))
+
+	for i := 0; i < 3; i++ {
+		log.Every(time.Hour).Warn("first site")
+		log.Every(time.Hour).Warn("second site")
+	}
+
+	if mock.Len() != 2 {
+		t.Errorf("expected one entry per call site, got %d", mock.Len())
+	}
+}
+
+func TestRateLimitedOnce(t *testing.T) {
+	mock := &mockSink{}
+	log := New(WithSink(mock), WithLevel(InfoLevel))
+
+	log.Once("config").Debug("disabled")
+	log.Once("config").Info("loaded")
+	log.With("child", true).Once("config").Info("loaded again")
+
+	if mock.Len() != 1 || mock.entries[0].Message != "loaded" {
+		t.Errorf("expected a single entry shared across the logger tree, got %d", mock.Len())
+	}
+}
+
+func TestRateLimitsBounded(t *testing.T) {
+	var rl rateLimits
+	now := time.Now()
+
+	for i := 0; i < maxLimitStates-1; i++ {
+		rl.take(limitKey{key: strconv.Itoa(i), policy: limitPolicy{every: time.Second}}, now)
+	}
+	once := limitKey{key: "once", policy: limitPolicy{once: true}}
+	rl.take(once, now)
+
+	// The Every limits have expired by now, so they make room first.
+	later := now.Add(2 * time.Second)
+	rl.take(limitKey{key: "new", policy: limitPolicy{once: true}}, later)
+	if len(rl.states) != 2 {
+		t.Errorf("expected expired limits to be dropped, got %d left", len(rl.states))
+	}
+	if _, ok := rl.take(once, later); ok {
+		t.Error("expected the Once limit to survive pruning")
+	}
+
+	for i := 0; i < 2*maxLimitStates; i++ {
+		rl.take(limitKey{key: "dyn" + strconv.Itoa(i), policy: limitPolicy{once: true}}, later.Add(time.Duration(i)))
+	}
+	if len(rl.states) > maxLimitStates {
+		t.Errorf("expected at most %d limits, got %d", maxLimitStates, len(rl.states))
+	}
+}
@@ -0,0 +1,360 @@
+package redact
+
+import (
+	"context"
+	"net/netip"
+	"regexp"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+
+	"github.com/godeh/sloggergo/formatter"
+)
+
+// Names of the built-in detectors.
+const (
+	DetectJWT         = "jwt"
+	DetectBearerToken = "bearer_token"
+	DetectCloudKey    = "cloud_key"
+	DetectEmail       = "email"
+	DetectCreditCard  = "credit_card"
+	DetectIBAN        = "iban"
+	DetectIPv4        = "ipv4"
+	DetectIPv6        = "ipv6"
+)
+
+// Detector finds one kind of sensitive data in free-form text.
+type Detector struct {
+	// Name identifies the detector in placeholders and counts.
+	Name string
+
+	// Pattern finds candidate matches.
+	Pattern *regexp.Regexp
+
+	// Group is the submatch of Pattern to replace; 0 replaces the whole
+	// match.
+	Group int
+
+	// Valid, if set, rejects candidates that are not real matches, such as
+	// card numbers that fail the Luhn check.
+	Valid func(match string) bool
+
+	// Joins, if set, rejects candidates that are directly preceded or
+	// followed by a rune it reports true for. It checks the surroundings
+	// of a match without consuming them, which regexp cannot do.
+	Joins func(r rune) bool
+}
+
+// Placeholder returns the text that replaces matches: [REDACTED:name].
+func (d Detector) Placeholder() string {
+	return "[REDACTED:" + d.Name + "]"
+}
+
+// Detectors returns the built-in detectors, in the order they run.
+// Tokens come first so that their contents are not reported as emails or
+// card numbers.
+func Detectors() []Detector {
+	return []Detector{
+		{
+			Name:    DetectJWT,
+			Pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+		},
+		{
+			Name:    DetectBearerToken,
+			Pattern: regexp.MustCompile(`(?i)\bbearer\s+([A-Za-z0-9\-._~+/]+=*)`),
+			Group:   1,
+		},
+		{
+			// AWS access key IDs, Google API keys, GitHub and Slack tokens.
+			Name: DetectCloudKey,
+			Pattern: regexp.MustCompile(`\b(?:(?:AKIA|ASIA|ABIA|ACCA)[A-Z0-9]{16}` +
+				`|AIza[0-9A-Za-z_\-]{35}` +
+				`|gh[pousr]_[A-Za-z0-9]{36}` +
+				`|xox[abprs]-[A-Za-z0-9-]{10,})\b`),
+		},
+		{
+			Name:    DetectEmail,
+			Pattern: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`),
+		},
+		{
+			Name:    DetectCreditCard,
+			Pattern: regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+			Valid:   validCard,
+		},
+		{
+			Name:    DetectIBAN,
+			Pattern: regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]){11,30}\b`),
+			Valid:   validIBAN,
+		},
+		{
+			Name:    DetectIPv4,
+			Pattern: regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`),
+			Valid:   validIP(netip.Addr.Is4),
+		},
+		{
+			// Standalone only, so that "std::string" is left alone.
+			Name:    DetectIPv6,
+			Pattern: regexp.MustCompile(`(?i)(?:[0-9a-f]{0,4}:){2,7}[0-9a-f]{0,4}`),
+			Valid: validIP(func(a netip.Addr) bool {
+				return a.Is6() && !a.IsUnspecified()
+			}),
+			Joins: func(r rune) bool {
+				return r == ':' || r < utf8.RuneSelf && isWordByte(byte(r))
+			},
+		},
+	}
+}
+
+// Scanner replaces sensitive data found by its detectors in messages and
+// string values with typed placeholders such as [REDACTED:email], and
+// counts the matches of each detector. It is safe for concurrent use.
+type Scanner struct {
+	detectors []Detector
+	counts    []atomic.Int64
+	redactor  *Redactor
+}
+
+// ScanOption configures a Scanner.
+type ScanOption func(*scanConfig)
+
+type scanConfig struct {
+	enabled  []string
+	disabled []string
+	custom   []Detector
+}
+
+// WithDetectors enables only the built-in detectors with the given names.
+// By default all of them are enabled.
+func WithDetectors(names ...string) ScanOption {
+	return func(c *scanConfig) {
+		c.enabled = append(c.enabled, names...)
+	}
+}
+
+// WithoutDetectors disables the built-in detectors with the given names.
+func WithoutDetectors(names ...string) ScanOption {
+	return func(c *scanConfig) {
+		c.disabled = append(c.disabled, names...)
+	}
+}
+
+// WithCustomDetector adds a detector that runs after the built-in ones.
+func WithCustomDetector(d Detector) ScanOption {
+	return func(c *scanConfig) {
+		c.custom = append(c.custom, d)
+	}
+}
+
+// NewScanner creates a scanner with the given detectors.
+func NewScanner(opts ...ScanOption) *Scanner {
+	var c scanConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	s := &Scanner{}
+	for _, d := range Detectors() {
+		if len(c.enabled) > 0 && !slices.Contains(c.enabled, d.Name) {
+			continue
+		}
+		if slices.Contains(c.disabled, d.Name) {
+			continue
+		}
+		s.detectors = append(s.detectors, d)
+	}
+	s.detectors = append(s.detectors, c.custom...)
+	s.counts = make([]atomic.Int64, len(s.detectors))
+	s.redactor = New(WithScanner(s))
+	return s
+}
+
+// Hook scans the entry's message and field values. Its signature matches
+// sloggergo.Hook.
+func (s *Scanner) Hook(ctx context.Context, entry *formatter.Entry) error {
+	return s.redactor.Hook(ctx, entry)
+}
+
+// Scan returns text with every match replaced by its placeholder, and
+// reports whether anything was found.
+func (s *Scanner) Scan(text string) (string, bool) {
+	found := false
+	for i, d := range s.detectors {
+		out, n := d.replace(text)
+		if n > 0 {
+			s.counts[i].Add(int64(n))
+			text, found = out, true
+		}
+	}
+	return text, found
+}
+
+// Counts returns the number of matches each detector has replaced so far.
+func (s *Scanner) Counts() map[string]int64 {
+	counts := make(map[string]int64, len(s.detectors))
+	for i, d := range s.detectors {
+		counts[d.Name] += s.counts[i].Load()
+	}
+	return counts
+}
+
+// replace replaces the valid matches of d in text and returns the result
+// and the number of matches.
+func (d Detector) replace(text string) (string, int) {
+	matches := d.Pattern.FindAllStringSubmatchIndex(text, -1)
+	if matches == nil {
+		return text, 0
+	}
+
+	var b strings.Builder
+	last, n := 0, 0
+	for _, m := range matches {
+		start, end := m[2*d.Group], m[2*d.Group+1]
+		if start < last {
+			// Already covered by a span found by retry.
+			continue
+		}
+		spans := [][2]int{{start, end}}
+		if !d.accept(text, start, end) {
+			spans = d.retry(text, start, end)
+		}
+		for _, sp := range spans {
+			b.WriteString(text[last:sp[0]])
+			b.WriteString(d.Placeholder())
+			last = sp[1]
+			n++
+		}
+	}
+	if n == 0 {
+		return text, 0
+	}
+	b.WriteString(text[last:])
+	return b.String(), n
+}
+
+// accept reports whether text[start:end] is a real match on its own.
+func (d Detector) accept(text string, start, end int) bool {
+	if start < 0 {
+		return false
+	}
+	if d.Joins != nil {
+		if r, size := utf8.DecodeLastRuneInString(text[:start]); size > 0 && d.Joins(r) {
+			return false
+		}
+		if r, size := utf8.DecodeRuneInString(text[end:]); size > 0 && d.Joins(r) {
+			return false
+		}
+	}
+	return d.Valid == nil || d.Valid(text[start:end])
+}
+
+// retry looks for real matches inside a candidate that failed as a whole,
+// such as a card number the pattern ran together with the digits before
+// it. It tries the spans of whole words within the candidate, leftmost
+// first and then longest first, keeping those that the pattern matches
+// entirely and that pass the checks. A span may run past the candidate
+// when the pattern, started at a later word, extends further.
+func (d Detector) retry(text string, start, end int) [][2]int {
+	if start < 0 {
+		return nil
+	}
+
+	var spans [][2]int
+	from := start
+	for s := start; s < end; s++ {
+		if s < from || !wordStart(text, s) {
+			continue
+		}
+		limit := end
+		if m := d.Pattern.FindStringSubmatchIndex(text[s:]); m != nil && m[2*d.Group] == 0 {
+			limit = max(limit, s+m[2*d.Group+1])
+		}
+		for e := limit; e > s; e-- {
+			if wordEnd(text, e) && d.matches(text[s:e]) && d.accept(text, s, e) {
+				spans = append(spans, [2]int{s, e})
+				from = e
+				break
+			}
+		}
+	}
+	return spans
+}
+
+// matches reports whether the pattern matches all of s.
+func (d Detector) matches(s string) bool {
+	m := d.Pattern.FindStringSubmatchIndex(s)
+	return m != nil && m[2*d.Group] == 0 && m[2*d.Group+1] == len(s)
+}
+
+// wordStart and wordEnd report whether a word begins or ends at byte i of
+// text, with words as in the regexp \b assertion.
+func wordStart(text string, i int) bool {
+	return i < len(text) && isWordByte(text[i]) && (i == 0 || !isWordByte(text[i-1]))
+}
+
+func wordEnd(text string, i int) bool {
+	return i > 0 && isWordByte(text[i-1]) && (i == len(text) || !isWordByte(text[i]))
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// validCard reports whether the digits of s form a card number that
+// passes the Luhn check.
+func validCard(s string) bool {
+	digits := strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, s)
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// validIBAN reports whether s is an IBAN with a valid mod-97 checksum.
+func validIBAN(s string) bool {
+	iban := strings.ReplaceAll(s, " ", "")
+	if len(iban) < 15 || len(iban) > 34 {
+		return false
+	}
+
+	// Move the country code and check digits to the end, read letters as
+	// the numbers 10 to 35, and take the remainder digit by digit.
+	rem := 0
+	for _, r := range iban[4:] + iban[:4] {
+		switch {
+		case r >= '0' && r <= '9':
+			rem = (rem*10 + int(r-'0')) % 97
+		case r >= 'A' && r <= 'Z':
+			rem = (rem*100 + int(r-'A'+10)) % 97
+		default:
+			return false
+		}
+	}
+	return rem == 1
+}
+
+func validIP(is func(netip.Addr) bool) func(string) bool {
+	return func(s string) bool {
+		addr, err := netip.ParseAddr(s)
+		return err == nil && is(addr)
+	}
+}
@@ -0,0 +1,333 @@
+// Package redact removes sensitive values from log entries.
+//
+// A Redactor matches fields by key name, glob or dotted path, and string
+// values by regular expression, and replaces what it finds using a
+// Strategy. Struct fields tagged `log:"redact"` are always redacted. It is
+// installed as a hook:
+//
+//	r := redact.New(
+//		redact.WithKeys("*password*", "auth.token"),
+//		redact.WithKeyStrategy("card_number", redact.Partial(4)),
+//	)
+//	log := sloggergo.New(sloggergo.WithHook(r.Hook, sloggergo.HookName("redact")))
+package redact
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/godeh/sloggergo/formatter"
+)
+
+// TagName is the struct tag that marks fields to redact: `log:"redact"`.
+const TagName = "log"
+
+// maxDepth bounds how deep the redactor walks nested values, which also
+// stops it on cyclic data.
+const maxDepth = 32
+
+// Redactor redacts log entries according to its rules.
+// It is safe for concurrent use once created.
+type Redactor struct {
+	keys     []keyRule
+	values   []valueRule
+	scanner  *Scanner
+	strategy Strategy
+}
+
+type keyRule struct {
+	pattern  string
+	path     bool
+	strategy Strategy
+}
+
+type valueRule struct {
+	re       *regexp.Regexp
+	strategy Strategy
+}
+
+// Option configures a Redactor.
+type Option func(*Redactor)
+
+// WithStrategy sets the strategy used by rules that do not name one, and
+// for tagged struct fields. The default is Mask.
+func WithStrategy(s Strategy) Option {
+	return func(r *Redactor) {
+		r.strategy = s
+	}
+}
+
+// WithKeys redacts fields whose key matches one of the patterns, using the
+// default strategy. Patterns are globs as in path.Match, compared without
+// regard to case. A pattern containing a dot is matched against the full
+// path of the field within groups and maps ("auth.token"); other patterns
+// are matched against the key alone, at any depth.
+func WithKeys(patterns ...string) Option {
+	return func(r *Redactor) {
+		for _, p := range patterns {
+			r.keys = append(r.keys, newKeyRule(p, nil))
+		}
+	}
+}
+
+// WithKeyStrategy redacts fields whose key matches pattern using s, or the
+// default strategy if s is nil.
+func WithKeyStrategy(pattern string, s Strategy) Option {
+	return func(r *Redactor) {
+		r.keys = append(r.keys, newKeyRule(pattern, s))
+	}
+}
+
+// WithValuePattern redacts the parts of string values that match re,
+// whatever their key, using s, or the default strategy if s is nil.
+func WithValuePattern(re *regexp.Regexp, s Strategy) Option {
+	return func(r *Redactor) {
+		r.values = append(r.values, valueRule{re: re, strategy: s})
+	}
+}
+
+// WithScanner also replaces sensitive data found by the scanner's
+// detectors in the message and in string values.
+func WithScanner(s *Scanner) Option {
+	return func(r *Redactor) {
+		r.scanner = s
+	}
+}
+
+// New creates a redactor with the given rules.
+func New(opts ...Option) *Redactor {
+	r := &Redactor{strategy: Mask()}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+func newKeyRule(pattern string, s Strategy) keyRule {
+	pattern = strings.ToLower(pattern)
+	return keyRule{pattern: pattern, path: strings.Contains(pattern, "."), strategy: s}
+}
+
+// Hook redacts the entry's fields, and its message if the redactor has a
+// scanner. Its signature matches sloggergo.Hook.
+func (r *Redactor) Hook(_ context.Context, entry *formatter.Entry) error {
+	if r.scanner != nil {
+		entry.Message, _ = r.scanner.Scan(entry.Message)
+	}
+	for k, v := range entry.Fields {
+		if nv, changed := r.field(k, k, v, 0); changed {
+			entry.Fields[k] = nv
+		}
+	}
+	return nil
+}
+
+// Value returns v with its sensitive parts redacted, as if it were logged
+// under key, and reports whether anything changed. The original value is
+// never modified.
+func (r *Redactor) Value(key string, v any) (any, bool) {
+	return r.field(key, key, v, 0)
+}
+
+// field redacts the value of the field key, found at keyPath.
+func (r *Redactor) field(key, keyPath string, v any, depth int) (any, bool) {
+	if s, ok := r.matchKey(key, keyPath); ok {
+		return s(stringify(v)), true
+	}
+	return r.value(keyPath, v, depth)
+}
+
+func (r *Redactor) matchKey(key, keyPath string) (Strategy, bool) {
+	if len(r.keys) == 0 {
+		return nil, false
+	}
+	key, keyPath = strings.ToLower(key), strings.ToLower(keyPath)
+	for _, rule := range r.keys {
+		name := key
+		if rule.path {
+			name = keyPath
+		}
+		if ok, _ := path.Match(rule.pattern, name); ok {
+			return r.or(rule.strategy), true
+		}
+	}
+	return nil, false
+}
+
+// value redacts the contents of v, which is stored at keyPath.
+func (r *Redactor) value(keyPath string, v any, depth int) (any, bool) {
+	if v == nil || depth >= maxDepth {
+		return v, false
+	}
+
+	switch x := v.(type) {
+	case string:
+		return r.redactString(x)
+	case formatter.Group:
+		return r.fields(keyPath, x, depth, func(m map[string]any) any { return formatter.Group(m) })
+	case map[string]any:
+		return r.fields(keyPath, x, depth, func(m map[string]any) any { return m })
+	case slog.LogValuer:
+		resolved := formatter.FieldValue(slog.AnyValue(x).Resolve())
+		if nv, changed := r.value(keyPath, resolved, depth+1); changed {
+			return nv, true
+		}
+		return v, false
+	case error, json.Marshaler, encoding.TextMarshaler:
+		// These render themselves; their fields are not what gets logged.
+		// Stringers are not among them, as JSON output ignores String.
+		return v, false
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+			return v, false
+		}
+		return r.value(keyPath, rv.Elem().Interface(), depth+1)
+	case reflect.Struct:
+		return r.structFields(keyPath, rv, depth)
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return v, false
+		}
+		m := make(map[string]any, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			m[iter.Key().String()] = iter.Value().Interface()
+		}
+		if nv, changed := r.fields(keyPath, m, depth, func(m map[string]any) any { return m }); changed {
+			return nv, true
+		}
+		return v, false
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return v, false
+		}
+		var out []any
+		for i := 0; i < rv.Len(); i++ {
+			ev := rv.Index(i).Interface()
+			nv, changed := r.value(keyPath, ev, depth+1)
+			if changed && out == nil {
+				out = make([]any, rv.Len())
+				for j := 0; j < i; j++ {
+					out[j] = rv.Index(j).Interface()
+				}
+			}
+			if out != nil {
+				out[i] = nv
+			}
+		}
+		if out == nil {
+			return v, false
+		}
+		return out, true
+	}
+	return v, false
+}
+
+// fields redacts a map of fields. If anything changed it returns a copy
+// built by wrap, leaving m untouched.
+func (r *Redactor) fields(keyPath string, m map[string]any, depth int, wrap func(map[string]any) any) (any, bool) {
+	var out map[string]any
+	for k, v := range m {
+		nv, changed := r.field(k, keyPath+"."+k, v, depth+1)
+		if !changed {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]any, len(m))
+			for k2, v2 := range m {
+				out[k2] = v2
+			}
+		}
+		out[k] = nv
+	}
+	if out == nil {
+		return m, false
+	}
+	return wrap(out), true
+}
+
+// structFields redacts a struct. If any field is redacted the struct is
+// replaced by a map of its exported fields, named as encoding/json would.
+func (r *Redactor) structFields(keyPath string, rv reflect.Value, depth int) (any, bool) {
+	t := rv.Type()
+	m := make(map[string]any, t.NumField())
+	changed := false
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name := sf.Name
+		if tag, _, _ := strings.Cut(sf.Tag.Get("json"), ","); tag == "-" {
+			continue
+		} else if tag != "" {
+			name = tag
+		}
+
+		v := rv.Field(i).Interface()
+		if hasRedactTag(sf) {
+			m[name] = r.strategy(stringify(v))
+			changed = true
+			continue
+		}
+		nv, c := r.field(name, keyPath+"."+name, v, depth+1)
+		m[name] = nv
+		changed = changed || c
+	}
+	if !changed {
+		return rv.Interface(), false
+	}
+	return m, true
+}
+
+func hasRedactTag(sf reflect.StructField) bool {
+	for _, opt := range strings.Split(sf.Tag.Get(TagName), ",") {
+		if opt == "redact" {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Redactor) redactString(s string) (any, bool) {
+	changed := false
+	for _, rule := range r.values {
+		strategy := r.or(rule.strategy)
+		out := rule.re.ReplaceAllStringFunc(s, strategy)
+		if out != s {
+			s, changed = out, true
+		}
+	}
+	if r.scanner != nil {
+		if out, found := r.scanner.Scan(s); found {
+			s, changed = out, true
+		}
+	}
+	return s, changed
+}
+
+// or returns s, or the default strategy if s is nil.
+func (r *Redactor) or(s Strategy) Strategy {
+	if s == nil {
+		return r.strategy
+	}
+	return s
+}
+
+func stringify(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
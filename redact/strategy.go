@@ -0,0 +1,69 @@
+package redact
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Redacted replaces values redacted with Mask.
+const Redacted = "[REDACTED]"
+
+// DefaultPartialKeep is the number of trailing characters Partial keeps
+// when the strategy is chosen by name.
+const DefaultPartialKeep = 4
+
+// Strategy turns a sensitive value, or the part of it matched by a value
+// pattern, into its replacement.
+type Strategy func(value string) string
+
+// Mask replaces the value with Redacted.
+func Mask() Strategy {
+	return func(string) string {
+		return Redacted
+	}
+}
+
+// Hash replaces the value with a prefix of its SHA-256 hash, so that equal
+// values can still be correlated. Short or guessable values can be
+// recovered from an unsalted hash; use Mask for those.
+func Hash() Strategy {
+	return func(value string) string {
+		sum := sha256.Sum256([]byte(value))
+		return "sha256:" + hex.EncodeToString(sum[:8])
+	}
+}
+
+// Partial keeps the last keep characters of the value and masks the rest
+// with '*'. Values of keep characters or fewer are masked completely.
+func Partial(keep int) Strategy {
+	return func(value string) string {
+		n := utf8.RuneCountInString(value)
+		if n <= keep {
+			return strings.Repeat("*", n)
+		}
+		tail := value
+		for i := 0; i < n-keep; i++ {
+			_, size := utf8.DecodeRuneInString(tail)
+			tail = tail[size:]
+		}
+		return strings.Repeat("*", n-keep) + tail
+	}
+}
+
+// ParseStrategy returns the strategy with the given name: mask, hash or
+// partial.
+func ParseStrategy(name string) (Strategy, error) {
+	switch strings.ToLower(name) {
+	case "", "mask":
+		return Mask(), nil
+	case "hash":
+		return Hash(), nil
+	case "partial":
+		return Partial(DefaultPartialKeep), nil
+	default:
+		return nil, fmt.Errorf("unknown redaction strategy: %s", name)
+	}
+}
@@ -0,0 +1,54 @@
+package sink
+
+import (
+	"sync"
+
+	"github.com/godeh/sloggergo/formatter"
+)
+
+// maxPooledBuffer is the largest buffer kept for reuse, so that one huge
+// entry does not pin its memory forever.
+const maxPooledBuffer = 64 << 10
+
+// buffer holds a formatted entry. Buffers are pooled and reused by the
+// sinks in this package.
+type buffer struct {
+	data []byte
+}
+
+var bufferPool = sync.Pool{
+	New: func() any {
+		return &buffer{data: make([]byte, 0, 1024)}
+	},
+}
+
+// formatEntry formats entry with f into a pooled buffer, which the caller
+// must free once the data has been written.
+func formatEntry(f formatter.Formatter, entry *formatter.Entry) (*buffer, error) {
+	b := bufferPool.Get().(*buffer)
+
+	var err error
+	if af, ok := f.(formatter.AppendFormatter); ok {
+		b.data, err = af.AppendFormat(b.data[:0], entry)
+	} else {
+		// Copy rather than keep the formatter's slice, which it may reuse
+		// or which may be far larger than the pool should hold on to.
+		var data []byte
+		data, err = f.Format(entry)
+		b.data = append(b.data[:0], data...)
+	}
+	if err != nil {
+		b.free()
+		return nil, err
+	}
+	return b, nil
+}
+
+// free returns the buffer to the pool.
+func (b *buffer) free() {
+	if cap(b.data) > maxPooledBuffer {
+		b.data = nil
+	}
+	b.data = b.data[:0]
+	bufferPool.Put(b)
+}
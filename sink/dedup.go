@@ -0,0 +1,190 @@
+package sink
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/godeh/sloggergo/formatter"
+)
+
+// Fields added to the summary entries written by DedupSink.
+const (
+	RepeatCountKey = "repeat_count"
+	FirstSeenKey   = "first_seen"
+	LastSeenKey    = "last_seen"
+)
+
+// DedupSink collapses repeated entries. The first entry with a given level,
+// message and selected fields is written at once and opens a window; the
+// same entry is dropped for the rest of the window. When the window ends,
+// if anything was dropped, a copy of the first entry is written with
+// repeat_count (the number of entries dropped), first_seen and last_seen
+// fields.
+type DedupSink struct {
+	sink   Sink
+	window time.Duration
+	fields []string
+
+	mu     sync.Mutex
+	seen   map[string]*dedupState
+	closed bool
+
+	// writing counts summaries being written by expired windows, which
+	// Close waits for before closing the wrapped sink.
+	writing sync.WaitGroup
+}
+
+type dedupState struct {
+	entry     *formatter.Entry
+	count     int
+	firstSeen time.Time
+	lastSeen  time.Time
+	timer     *time.Timer
+}
+
+// DedupOption configures a DedupSink.
+type DedupOption func(*DedupSink)
+
+// WithDedupFields makes the given fields part of what makes entries
+// identical, in addition to the level and message.
+func WithDedupFields(keys ...string) DedupOption {
+	return func(d *DedupSink) {
+		d.fields = append(d.fields, keys...)
+	}
+}
+
+// NewDedup creates a sink that writes to s, collapsing entries repeated
+// within window.
+func NewDedup(s Sink, window time.Duration, opts ...DedupOption) *DedupSink {
+	d := &DedupSink{
+		sink:   s,
+		window: window,
+		seen:   make(map[string]*dedupState),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// Write writes the entry unless it repeats one already written in the
+// current window.
+func (d *DedupSink) Write(entry *formatter.Entry) error {
+	key := d.key(entry)
+
+	d.mu.Lock()
+	if st, ok := d.seen[key]; ok {
+		st.count++
+		st.lastSeen = entry.Time
+		d.mu.Unlock()
+		return nil
+	}
+	if !d.closed {
+		st := &dedupState{entry: entry.Clone(), firstSeen: entry.Time}
+		st.timer = time.AfterFunc(d.window, func() { d.expire(key, st) })
+		d.seen[key] = st
+	}
+	d.mu.Unlock()
+
+	return d.sink.Write(entry)
+}
+
+// key identifies entries that count as repeats of each other.
+func (d *DedupSink) key(entry *formatter.Entry) string {
+	var b strings.Builder
+	b.WriteString(entry.Level)
+	b.WriteByte(0)
+	b.WriteString(entry.Message)
+	for _, k := range d.fields {
+		b.WriteByte(0)
+		b.WriteString(k)
+		b.WriteByte('=')
+		fmt.Fprint(&b, entry.Fields[k])
+	}
+	return b.String()
+}
+
+// expire ends the window of st and writes its summary.
+func (d *DedupSink) expire(key string, st *dedupState) {
+	d.mu.Lock()
+	if d.seen[key] != st {
+		d.mu.Unlock()
+		return
+	}
+	delete(d.seen, key)
+	d.writing.Add(1)
+	d.mu.Unlock()
+	defer d.writing.Done()
+
+	// The sink has no logger to report errors to; the next entry that
+	// fails will surface the problem.
+	_ = d.writeSummary(st)
+}
+
+// writeSummary writes the summary of st if any repeats were dropped.
+func (d *DedupSink) writeSummary(st *dedupState) error {
+	if st.count == 0 {
+		return nil
+	}
+
+	summary := st.entry
+	summary.Time = time.Now()
+	for _, a := range []slog.Attr{
+		slog.Int(RepeatCountKey, st.count),
+		slog.Time(FirstSeenKey, st.firstSeen),
+		slog.Time(LastSeenKey, st.lastSeen),
+	} {
+		summary.Attrs = append(summary.Attrs, a)
+		if summary.Fields != nil {
+			summary.Fields[a.Key] = a.Value.Any()
+		}
+	}
+	return d.sink.Write(summary)
+}
+
+// Flush ends every open window, writing the pending summaries.
+func (d *DedupSink) Flush() error {
+	d.mu.Lock()
+	pending := make([]*dedupState, 0, len(d.seen))
+	for key, st := range d.seen {
+		st.timer.Stop()
+		pending = append(pending, st)
+		delete(d.seen, key)
+	}
+	d.mu.Unlock()
+
+	var firstErr error
+	for _, st := range pending {
+		if err := d.writeSummary(st); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Sync flushes the wrapped sink if it buffers output. Open windows are
+// left open; use Flush to end them.
+func (d *DedupSink) Sync() error {
+	if s, ok := d.sink.(Syncer); ok {
+		return s.Sync()
+	}
+	return nil
+}
+
+// Close writes the pending summaries, waits for those already being
+// written, and closes the wrapped sink.
+func (d *DedupSink) Close() error {
+	d.mu.Lock()
+	d.closed = true
+	d.mu.Unlock()
+
+	err := d.Flush()
+	d.writing.Wait()
+	if cerr := d.sink.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
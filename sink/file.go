@@ -53,18 +53,30 @@ func NewFile(path string, opts ...FileOption) (*FileSink, error) {
 
 // Write writes the entry to the file.
 func (s *FileSink) Write(entry *formatter.Entry) error {
-	data, err := s.formatter.Format(entry)
+	buf, err := formatEntry(s.formatter, entry)
 	if err != nil {
 		return err
 	}
+	defer buf.free()
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	_, err = s.file.Write(data)
+	_, err = s.file.Write(buf.data)
 	return err
 }
 
+// Sync commits the file's contents to stable storage.
+func (s *FileSink) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file != nil {
+		return s.file.Sync()
+	}
+	return nil
+}
+
 // Close closes the file.
 func (s *FileSink) Close() error {
 	s.mu.Lock()
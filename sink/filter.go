@@ -0,0 +1,72 @@
+package sink
+
+import (
+	"github.com/godeh/sloggergo/formatter"
+	"github.com/godeh/sloggergo/level"
+)
+
+// FilterSink passes entries on to another sink only if they reach a
+// minimum level and satisfy an optional predicate. Use it to send errors
+// to an alerting sink while stdout receives everything:
+//
+//	logger.AddSink(sink.NewFilter(alerts, sink.WithMinLevel(level.Error)))
+type FilterSink struct {
+	sink      Sink
+	minLevel  level.Level
+	hasMin    bool
+	predicate func(*formatter.Entry) bool
+}
+
+// FilterOption configures a FilterSink.
+type FilterOption func(*FilterSink)
+
+// WithMinLevel drops entries below the given level.
+// Entries whose level name is not registered are passed on.
+func WithMinLevel(l level.Level) FilterOption {
+	return func(f *FilterSink) {
+		f.minLevel = l
+		f.hasMin = true
+	}
+}
+
+// WithPredicate drops entries for which fn returns false.
+func WithPredicate(fn func(*formatter.Entry) bool) FilterOption {
+	return func(f *FilterSink) {
+		f.predicate = fn
+	}
+}
+
+// NewFilter creates a sink that filters entries before writing them to s.
+func NewFilter(s Sink, opts ...FilterOption) *FilterSink {
+	f := &FilterSink{sink: s}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// Write writes the entry to the wrapped sink if it passes the filter.
+func (f *FilterSink) Write(entry *formatter.Entry) error {
+	if f.hasMin {
+		if l, err := level.Parse(entry.Level); err == nil && l < f.minLevel {
+			return nil
+		}
+	}
+	if f.predicate != nil && !f.predicate(entry) {
+		return nil
+	}
+	return f.sink.Write(entry)
+}
+
+// Sync flushes the wrapped sink if it buffers output.
+func (f *FilterSink) Sync() error {
+	if s, ok := f.sink.(Syncer); ok {
+		return s.Sync()
+	}
+	return nil
+}
+
+// Close closes the wrapped sink.
+func (f *FilterSink) Close() error {
+	return f.sink.Close()
+}
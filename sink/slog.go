@@ -0,0 +1,51 @@
+package sink
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/godeh/sloggergo/formatter"
+	"github.com/godeh/sloggergo/level"
+)
+
+// SlogSink forwards log entries to an slog.Handler.
+type SlogSink struct {
+	handler slog.Handler
+}
+
+// NewSlog creates a sink that writes entries through the given slog.Handler,
+// such as slog.NewJSONHandler or a vendor handler.
+func NewSlog(h slog.Handler) *SlogSink {
+	return &SlogSink{handler: h}
+}
+
+// Write converts the entry into an slog.Record and passes it to the handler.
+func (s *SlogSink) Write(entry *formatter.Entry) error {
+	ctx := entry.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	lvl, err := level.Parse(entry.Level)
+	if err != nil {
+		lvl = level.Info
+	}
+	if !s.handler.Enabled(ctx, lvl.Slog()) {
+		return nil
+	}
+
+	r := slog.NewRecord(entry.Time, lvl.Slog(), entry.Message, 0)
+
+	r.AddAttrs(entry.Attributes()...)
+
+	if entry.Caller != "" {
+		r.AddAttrs(slog.String("caller", entry.Caller))
+	}
+
+	return s.handler.Handle(ctx, r)
+}
+
+// Close is a no-op; slog handlers have no close method.
+func (s *SlogSink) Close() error {
+	return nil
+}
@@ -9,11 +9,20 @@ import (
 )
 
 // Sink defines the interface for log output destinations.
+//
+// The logger reuses entries, so an entry passed to Write is only valid
+// until Write returns. Sinks that keep entries must keep entry.Clone().
 type Sink interface {
 	Write(entry *formatter.Entry) error
 	Close() error
 }
 
+// Syncer is implemented by sinks that buffer output and can flush it to
+// its destination, such as FileSink. Logger.Sync calls it on every sink.
+type Syncer interface {
+	Sync() error
+}
+
 // StdoutSink writes log entries to stdout.
 type StdoutSink struct {
 	mu        sync.Mutex
@@ -52,15 +61,16 @@ func NewStdout(opts ...StdoutOption) *StdoutSink {
 
 // Write writes the entry to stdout.
 func (s *StdoutSink) Write(entry *formatter.Entry) error {
-	data, err := s.formatter.Format(entry)
+	buf, err := formatEntry(s.formatter, entry)
 	if err != nil {
 		return err
 	}
+	defer buf.free()
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	_, err = s.writer.Write(data)
+	_, err = s.writer.Write(buf.data)
 	return err
 }
 
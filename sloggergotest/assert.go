@@ -0,0 +1,59 @@
+package sloggergotest
+
+import (
+	"testing"
+
+	"github.com/godeh/sloggergo"
+	"github.com/godeh/sloggergo/formatter"
+)
+
+// AssertLogged fails the test unless the recorder holds an entry with the
+// given level and message, and returns the first such entry.
+func AssertLogged(t testing.TB, r *Recorder, level sloggergo.Level, msg string) *formatter.Entry {
+	t.Helper()
+	matches := r.Entries().FilterLevel(level).FilterMessage(msg)
+	if len(matches) == 0 {
+		t.Fatalf("no %s entry with message %q; recorded: %q", level, msg, r.Entries().Messages())
+		return nil
+	}
+	return matches[0]
+}
+
+// AssertNotLogged fails the test if the recorder holds an entry with the
+// given level and message.
+func AssertNotLogged(t testing.TB, r *Recorder, level sloggergo.Level, msg string) {
+	t.Helper()
+	if n := len(r.Entries().FilterLevel(level).FilterMessage(msg)); n > 0 {
+		t.Errorf("expected no %s entry with message %q, found %d", level, msg, n)
+	}
+}
+
+// AssertCount fails the test unless es holds exactly n entries.
+func AssertCount(t testing.TB, es Entries, n int) {
+	t.Helper()
+	if len(es) != n {
+		t.Errorf("expected %d entries, got %d: %q", n, len(es), es.Messages())
+	}
+}
+
+// AssertField fails the test unless the entry has field key equal to want,
+// compared as in Entries.FilterField.
+func AssertField(t testing.TB, e *formatter.Entry, key string, want any) {
+	t.Helper()
+	got, ok := Field(e, key)
+	if !ok {
+		t.Errorf("entry %q has no field %q", e.Message, key)
+		return
+	}
+	if !equalValues(got, want) {
+		t.Errorf("entry %q: field %q is %v (%T), want %v (%T)", e.Message, key, got, got, want, want)
+	}
+}
+
+// AssertNoField fails the test if the entry has field key.
+func AssertNoField(t testing.TB, e *formatter.Entry, key string) {
+	t.Helper()
+	if got, ok := Field(e, key); ok {
+		t.Errorf("entry %q: expected no field %q, got %v", e.Message, key, got)
+	}
+}
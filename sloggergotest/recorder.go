@@ -0,0 +1,160 @@
+// Package sloggergotest provides an in-memory sink and assertions for
+// testing code that logs with sloggergo.
+//
+//	func TestCheckout(t *testing.T) {
+//		log, rec := sloggergotest.New(t, sloggergotest.WithTestLog())
+//		checkout(log)
+//		e := sloggergotest.AssertLogged(t, rec, sloggergo.InfoLevel, "order placed")
+//		sloggergotest.AssertField(t, e, "total", 42)
+//	}
+package sloggergotest
+
+import (
+	"log/slog"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/godeh/sloggergo"
+	"github.com/godeh/sloggergo/formatter"
+)
+
+// Recorder is a sink that keeps every entry written to it.
+// It is safe for concurrent use.
+type Recorder struct {
+	mu      sync.Mutex
+	entries Entries
+}
+
+// NewRecorder creates an empty recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Write records a copy of the entry.
+func (r *Recorder) Write(entry *formatter.Entry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, entry.Clone())
+	return nil
+}
+
+// Close is a no-op; recorded entries stay available.
+func (r *Recorder) Close() error {
+	return nil
+}
+
+// Entries returns the entries recorded so far, oldest first.
+func (r *Recorder) Entries() Entries {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append(Entries(nil), r.entries...)
+}
+
+// Len returns the number of entries recorded so far.
+func (r *Recorder) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.entries)
+}
+
+// Reset discards the recorded entries.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = nil
+}
+
+// Entries is a list of recorded entries. The Filter methods return the
+// entries that match, in order, and can be chained.
+type Entries []*formatter.Entry
+
+// Filter returns the entries for which fn returns true.
+func (es Entries) Filter(fn func(*formatter.Entry) bool) Entries {
+	var out Entries
+	for _, e := range es {
+		if fn(e) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// FilterLevel returns the entries logged at level.
+func (es Entries) FilterLevel(level sloggergo.Level) Entries {
+	name := level.String()
+	return es.Filter(func(e *formatter.Entry) bool { return e.Level == name })
+}
+
+// FilterMessage returns the entries with exactly the given message.
+func (es Entries) FilterMessage(msg string) Entries {
+	return es.Filter(func(e *formatter.Entry) bool { return e.Message == msg })
+}
+
+// FilterMessageContains returns the entries whose message contains substr.
+func (es Entries) FilterMessageContains(substr string) Entries {
+	return es.Filter(func(e *formatter.Entry) bool { return strings.Contains(e.Message, substr) })
+}
+
+// FilterField returns the entries whose field key equals value. Fields in
+// groups are addressed with dots ("http.status"), and numbers compare by
+// value, so 200 matches a field logged as int64(200).
+func (es Entries) FilterField(key string, value any) Entries {
+	return es.Filter(func(e *formatter.Entry) bool {
+		v, ok := Field(e, key)
+		return ok && equalValues(v, value)
+	})
+}
+
+// Messages returns the message of each entry.
+func (es Entries) Messages() []string {
+	msgs := make([]string, len(es))
+	for i, e := range es {
+		msgs[i] = e.Message
+	}
+	return msgs
+}
+
+// Field returns the value of a field of the entry, with dots separating
+// group names from the field name.
+func Field(e *formatter.Entry, key string) (any, bool) {
+	fields := e.Fields
+	for {
+		name, rest, nested := strings.Cut(key, ".")
+		v, ok := fields[name]
+		if !ok {
+			// The key itself may contain dots.
+			v, ok = fields[key]
+			return v, ok
+		}
+		if !nested {
+			return v, true
+		}
+		g, isGroup := v.(formatter.Group)
+		if !isGroup {
+			return nil, false
+		}
+		fields, key = g, rest
+	}
+}
+
+// equalValues compares a field value with an expected value, treating
+// numbers, strings, durations and times like slog does.
+func equalValues(got, want any) bool {
+	gv, wv := slog.AnyValue(got), slog.AnyValue(want)
+	if gv.Kind() != slog.KindAny && wv.Kind() != slog.KindAny {
+		if gv.Kind() == wv.Kind() {
+			return gv.Equal(wv)
+		}
+		// Mixed signed and unsigned integers.
+		if isInteger(gv) && isInteger(wv) {
+			return gv.String() == wv.String()
+		}
+		return false
+	}
+	return reflect.DeepEqual(got, want)
+}
+
+func isInteger(v slog.Value) bool {
+	return v.Kind() == slog.KindInt64 || v.Kind() == slog.KindUint64
+}
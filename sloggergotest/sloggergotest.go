@@ -0,0 +1,91 @@
+package sloggergotest
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/godeh/sloggergo"
+	"github.com/godeh/sloggergo/formatter"
+)
+
+type config struct {
+	mirror bool
+	opts   []sloggergo.Option
+}
+
+// Option configures New.
+type Option func(*config)
+
+// WithTestLog also writes every entry to t.Log, so that the test's log
+// output shows it when the test fails or runs with -v.
+func WithTestLog() Option {
+	return func(c *config) {
+		c.mirror = true
+	}
+}
+
+// WithLoggerOptions passes options to sloggergo.New. They are applied after
+// the defaults, so they can change the level, for example.
+func WithLoggerOptions(opts ...sloggergo.Option) Option {
+	return func(c *config) {
+		c.opts = append(c.opts, opts...)
+	}
+}
+
+// New returns a logger that records every entry, at all levels, in the
+// returned Recorder. The logger is closed when the test ends.
+func New(t testing.TB, opts ...Option) (*sloggergo.Logger, *Recorder) {
+	var c config
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	rec := NewRecorder()
+	loggerOpts := []sloggergo.Option{
+		sloggergo.WithLevel(sloggergo.TraceLevel),
+		sloggergo.WithSink(rec),
+	}
+	if c.mirror {
+		loggerOpts = append(loggerOpts, sloggergo.WithSink(newTestLogSink(t)))
+	}
+	loggerOpts = append(loggerOpts, c.opts...)
+
+	log := sloggergo.New(loggerOpts...)
+	t.Cleanup(func() { log.Close() })
+	return log, rec
+}
+
+// testLogSink writes entries to t.Log as text.
+type testLogSink struct {
+	t         testing.TB
+	formatter *formatter.TextFormatter
+	done      atomic.Bool
+}
+
+func newTestLogSink(t testing.TB) *testLogSink {
+	s := &testLogSink{
+		t:         t,
+		formatter: &formatter.TextFormatter{DisableColors: true, DisableTimestamp: true},
+	}
+	// t.Log panics once the test has finished, which goroutines that
+	// outlive the test can still trigger.
+	t.Cleanup(func() { s.done.Store(true) })
+	return s
+}
+
+func (s *testLogSink) Write(entry *formatter.Entry) error {
+	if s.done.Load() {
+		return nil
+	}
+	data, err := s.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	s.t.Log(strings.TrimSuffix(string(data), "\n"))
+	return nil
+}
+
+func (s *testLogSink) Close() error {
+	return nil
+}
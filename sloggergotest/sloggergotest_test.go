@@ -0,0 +1,32 @@
+package sloggergotest
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/godeh/sloggergo"
+)
+
+func TestRecorder(t *testing.T) {
+	log, rec := New(t, WithTestLog())
+
+	log.Debug("starting")
+	log.WithGroup("http").Info("request", slog.Int("status", 200), slog.Duration("took", time.Second))
+	log.Warn("slow request", slog.String("path", "/orders"))
+
+	AssertCount(t, rec.Entries(), 3)
+	AssertNotLogged(t, rec, sloggergo.ErrorLevel, "slow request")
+
+	e := AssertLogged(t, rec, sloggergo.InfoLevel, "request")
+	AssertField(t, e, "http.status", 200)
+	AssertField(t, e, "http.took", time.Second)
+	AssertNoField(t, e, "status")
+
+	warnings := rec.Entries().FilterField("path", "/orders")
+	AssertCount(t, warnings, 1)
+	AssertCount(t, rec.Entries().FilterMessageContains("request"), 2)
+
+	rec.Reset()
+	AssertCount(t, rec.Entries(), 0)
+}
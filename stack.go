@@ -0,0 +1,108 @@
+package sloggergo
+
+import (
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/godeh/sloggergo/formatter"
+)
+
+const (
+	// stackKey is the field that holds a captured stack trace.
+	stackKey = "stacktrace"
+
+	// DefaultStacktraceDepth is the number of frames kept when no depth
+	// is set with WithStacktraceDepth.
+	DefaultStacktraceDepth = 32
+
+	// modulePath prefixes the function names of this module's frames.
+	modulePath = "github.com/godeh/sloggergo"
+)
+
+// stackOptions controls stack trace capture.
+type stackOptions struct {
+	enabled bool
+	min     Level
+	depth   int
+}
+
+// WithStacktrace attaches a stack trace, as the "stacktrace" field, to
+// entries at level min or above. Frames from the Go runtime, log/slog and
+// the logger itself are left out.
+func WithStacktrace(min Level) Option {
+	return func(l *Logger) {
+		l.stack.enabled = true
+		l.stack.min = min
+	}
+}
+
+// WithStacktraceDepth limits stack traces to n frames.
+// The default is DefaultStacktraceDepth.
+func WithStacktraceDepth(n int) Option {
+	return func(l *Logger) {
+		l.stack.depth = n
+	}
+}
+
+// wantStack reports whether entries at the given level get a stack trace.
+func (o stackOptions) wantStack(level Level) bool {
+	return o.enabled && level >= o.min
+}
+
+// captureStack returns up to depth frames of the calling goroutine's stack,
+// skipping frames dropped by keepFrame.
+func captureStack(depth int) formatter.Stack {
+	if depth <= 0 {
+		depth = DefaultStacktraceDepth
+	}
+
+	// Leave room for the logger's own frames, which are filtered out.
+	pcs := make([]uintptr, depth+16)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	stack := make(formatter.Stack, 0, depth)
+	for len(stack) < depth {
+		frame, more := frames.Next()
+		if keepFrame(frame.Function) {
+			stack = append(stack, formatter.Frame{
+				Function: frame.Function,
+				File:     frame.File,
+				Line:     frame.Line,
+			})
+		}
+		if !more {
+			break
+		}
+	}
+	return stack
+}
+
+// libraryPackages are the sub-packages of this module whose frames are
+// left out of stack traces. Other code under the module, such as the
+// examples, is kept.
+var libraryPackages = []string{"config", "formatter", "level", "redact", "sink", "sloggergotest"}
+
+// keepFrame reports whether a frame belongs in a stack trace. Runtime and
+// log/slog frames are dropped, as are the methods of the logger types and
+// the library's sub-packages.
+func keepFrame(function string) bool {
+	switch {
+	case function == "":
+		return false
+	case strings.HasPrefix(function, "runtime."),
+		strings.HasPrefix(function, "log/slog."),
+		strings.HasPrefix(function, modulePath+".(*"),
+		strings.HasPrefix(function, modulePath+".RateLimited."):
+		return false
+	}
+	if rest, ok := strings.CutPrefix(function, modulePath+"/"); ok {
+		pkg := rest
+		if i := strings.IndexAny(rest, "./"); i >= 0 {
+			pkg = rest[:i]
+		}
+		return !slices.Contains(libraryPackages, pkg)
+	}
+	return true
+}
@@ -0,0 +1,99 @@
+package sloggergo
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/godeh/sloggergo/formatter"
+)
+
+func TestStacktrace(t *testing.T) {
+	mock := &mockSink{}
+	log := New(WithSink(mock), WithStacktrace(ErrorLevel), WithStacktraceDepth(2))
+
+	log.Warn("no trace")
+	log.Error("with trace")
+
+	if _, ok := mock.entries[0].Fields[stackKey]; ok {
+		t.Error("expected no stack trace below the threshold")
+	}
+	stack, ok := mock.entries[1].Fields[stackKey].(formatter.Stack)
+	if !ok {
+		t.Fatalf("expected a stack trace, got %v", mock.entries[1].Fields)
+	}
+	if len(stack) != 2 {
+		t.Errorf("expected 2 frames, got %d", len(stack))
+	}
+	if !strings.HasSuffix(stack[0].Function, ".TestStacktrace") {
+		t.Errorf("expected the first frame to be the caller, got %s", stack[0].Function)
+	}
+	for _, fr := range stack {
+		if !keepFrame(fr.Function) {
+			t.Errorf("unexpected frame %s", fr.Function)
+		}
+	}
+}
+
+func TestStacktraceRateLimited(t *testing.T) {
+	mock := &mockSink{}
+	log := New(WithSink(mock), WithStacktrace(ErrorLevel))
+
+	log.Every(time.Second).Error("boom")
+
+	stack := mock.entries[0].Fields[stackKey].(formatter.Stack)
+	if len(stack) == 0 || !strings.HasSuffix(stack[0].Function, ".TestStacktraceRateLimited") {
+		t.Errorf("expected the first frame to be the caller, got %v", stack)
+	}
+}
+
+func TestKeepFrame(t *testing.T) {
+	tests := map[string]bool{
+		"main.run":                               true,
+		modulePath + ".TestKeepFrame":            true,
+		modulePath + "/examples/basic.main":      true,
+		modulePath + ".(*Logger).Error":          false,
+		modulePath + ".RateLimited.Error":        false,
+		modulePath + "/sink.(*StdoutSink).Write": false,
+		modulePath + "/redact.(*Redactor).Hook":  false,
+		"runtime.goexit":                         false,
+	}
+	for function, want := range tests {
+		if got := keepFrame(function); got != want {
+			t.Errorf("keepFrame(%q) = %v, want %v", function, got, want)
+		}
+	}
+}
+
+func TestFormatStacktrace(t *testing.T) {
+	entry := &formatter.Entry{
+		Time:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Level:   "ERROR",
+		Message: "failed",
+		Fields: map[string]any{
+			"id":         1,
+			"stacktrace": formatter.Stack{{Function: "main.run", File: "/app/main.go", Line: 42}},
+		},
+	}
+
+	text, _ := (&formatter.TextFormatter{DisableColors: true, DisableTimestamp: true}).Format(entry)
+	want := "ERROR failed id=1\n    stacktrace:\n        main.run\n            /app/main.go:42\n"
+	if string(text) != want {
+		t.Errorf("text output:\n%q\nwant:\n%q", text, want)
+	}
+
+	data, _ := formatter.NewJSON().Format(entry)
+	var out struct {
+		Fields struct {
+			Stacktrace []map[string]any `json:"stacktrace"`
+		} `json:"fields"`
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(data), &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Fields.Stacktrace) != 1 || out.Fields.Stacktrace[0]["function"] != "main.run" || out.Fields.Stacktrace[0]["line"] != float64(42) {
+		t.Errorf("unexpected JSON stack trace: %s", data)
+	}
+}
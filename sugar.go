@@ -0,0 +1,168 @@
+package sloggergo
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// badKey is the key used for arguments that are not part of a valid
+// key/value pair, as in log/slog.
+const badKey = "!BADKEY"
+
+// argsToAttrs converts alternating keys and values into attributes with
+// the same rules as slog.Logger: a string is a key followed by its value,
+// an slog.Attr stands on its own, and anything else, including a final key
+// without a value, is reported under !BADKEY. Keys are unique on an entry,
+// so when there are several such arguments !BADKEY holds all of them, in
+// order, as a []any.
+func argsToAttrs(args []any) []slog.Attr {
+	var attrs []slog.Attr
+	var bad []any
+	badAt := -1
+	addBad := func(v any) {
+		if badAt < 0 {
+			badAt = len(attrs)
+			attrs = append(attrs, slog.Any(badKey, v))
+		}
+		bad = append(bad, v)
+	}
+	for len(args) > 0 {
+		switch x := args[0].(type) {
+		case string:
+			if len(args) == 1 {
+				addBad(x)
+				args = nil
+				continue
+			}
+			attrs = append(attrs, slog.Any(x, args[1]))
+			args = args[2:]
+		case slog.Attr:
+			attrs = append(attrs, x)
+			args = args[1:]
+		default:
+			addBad(x)
+			args = args[1:]
+		}
+	}
+	if len(bad) > 1 {
+		attrs[badAt] = slog.Any(badKey, bad)
+	}
+	return attrs
+}
+
+// Logf logs a formatted message at any level.
+func (l *Logger) Logf(ctx context.Context, level Level, format string, args ...any) {
+	if l.enabled(level) || level == PanicLevel || level == FatalLevel {
+		l.log(ctx, level, fmt.Sprintf(format, args...))
+	}
+}
+
+// Logw logs a message at any level with alternating keys and values.
+func (l *Logger) Logw(ctx context.Context, level Level, msg string, keysAndValues ...any) {
+	l.log(ctx, level, msg, argsToAttrs(keysAndValues)...)
+}
+
+// Tracef logs a formatted trace message.
+func (l *Logger) Tracef(format string, args ...any) {
+	if l.enabled(TraceLevel) {
+		l.log(context.Background(), TraceLevel, fmt.Sprintf(format, args...))
+	}
+}
+
+// Tracew logs a trace message with alternating keys and values.
+func (l *Logger) Tracew(msg string, keysAndValues ...any) {
+	l.log(context.Background(), TraceLevel, msg, argsToAttrs(keysAndValues)...)
+}
+
+// Debugf logs a formatted debug message.
+func (l *Logger) Debugf(format string, args ...any) {
+	if l.enabled(DebugLevel) {
+		l.log(context.Background(), DebugLevel, fmt.Sprintf(format, args...))
+	}
+}
+
+// Debugw logs a debug message with alternating keys and values.
+func (l *Logger) Debugw(msg string, keysAndValues ...any) {
+	l.log(context.Background(), DebugLevel, msg, argsToAttrs(keysAndValues)...)
+}
+
+// Infof logs a formatted info message.
+func (l *Logger) Infof(format string, args ...any) {
+	if l.enabled(InfoLevel) {
+		l.log(context.Background(), InfoLevel, fmt.Sprintf(format, args...))
+	}
+}
+
+// Infow logs an info message with alternating keys and values.
+func (l *Logger) Infow(msg string, keysAndValues ...any) {
+	l.log(context.Background(), InfoLevel, msg, argsToAttrs(keysAndValues)...)
+}
+
+// Noticef logs a formatted notice message.
+func (l *Logger) Noticef(format string, args ...any) {
+	if l.enabled(NoticeLevel) {
+		l.log(context.Background(), NoticeLevel, fmt.Sprintf(format, args...))
+	}
+}
+
+// Noticew logs a notice message with alternating keys and values.
+func (l *Logger) Noticew(msg string, keysAndValues ...any) {
+	l.log(context.Background(), NoticeLevel, msg, argsToAttrs(keysAndValues)...)
+}
+
+// Warnf logs a formatted warning message.
+func (l *Logger) Warnf(format string, args ...any) {
+	if l.enabled(WarnLevel) {
+		l.log(context.Background(), WarnLevel, fmt.Sprintf(format, args...))
+	}
+}
+
+// Warnw logs a warning message with alternating keys and values.
+func (l *Logger) Warnw(msg string, keysAndValues ...any) {
+	l.log(context.Background(), WarnLevel, msg, argsToAttrs(keysAndValues)...)
+}
+
+// Errorf logs a formatted error message.
+func (l *Logger) Errorf(format string, args ...any) {
+	if l.enabled(ErrorLevel) {
+		l.log(context.Background(), ErrorLevel, fmt.Sprintf(format, args...))
+	}
+}
+
+// Errorw logs an error message with alternating keys and values.
+func (l *Logger) Errorw(msg string, keysAndValues ...any) {
+	l.log(context.Background(), ErrorLevel, msg, argsToAttrs(keysAndValues)...)
+}
+
+// Criticalf logs a formatted critical message.
+func (l *Logger) Criticalf(format string, args ...any) {
+	if l.enabled(CriticalLevel) {
+		l.log(context.Background(), CriticalLevel, fmt.Sprintf(format, args...))
+	}
+}
+
+// Criticalw logs a critical message with alternating keys and values.
+func (l *Logger) Criticalw(msg string, keysAndValues ...any) {
+	l.log(context.Background(), CriticalLevel, msg, argsToAttrs(keysAndValues)...)
+}
+
+// Panicf logs a formatted panic message and then panics.
+func (l *Logger) Panicf(format string, args ...any) {
+	l.log(context.Background(), PanicLevel, fmt.Sprintf(format, args...))
+}
+
+// Panicw logs a panic message with alternating keys and values and then panics.
+func (l *Logger) Panicw(msg string, keysAndValues ...any) {
+	l.log(context.Background(), PanicLevel, msg, argsToAttrs(keysAndValues)...)
+}
+
+// Fatalf logs a formatted fatal message and exits.
+func (l *Logger) Fatalf(format string, args ...any) {
+	l.log(context.Background(), FatalLevel, fmt.Sprintf(format, args...))
+}
+
+// Fatalw logs a fatal message with alternating keys and values and exits.
+func (l *Logger) Fatalw(msg string, keysAndValues ...any) {
+	l.log(context.Background(), FatalLevel, msg, argsToAttrs(keysAndValues)...)
+}
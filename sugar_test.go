@@ -0,0 +1,141 @@
+package sloggergo
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/godeh/sloggergo/formatter"
+)
+
+func TestFormattedMethods(t *testing.T) {
+	mock := &mockSink{}
+	log := New(WithLevel(InfoLevel), WithSink(mock))
+
+	log.Debugf("hidden %d", 1)
+	log.Infof("user %s logged in after %d attempts", "bob", 3)
+
+	if mock.Len() != 1 {
+		t.Fatalf("expected 1 entry, got %d", mock.Len())
+	}
+	if got := mock.entries[0].Message; got != "user bob logged in after 3 attempts" {
+		t.Errorf("unexpected message %q", got)
+	}
+}
+
+func TestKeyValueMethods(t *testing.T) {
+	mock := &mockSink{}
+	log := New(WithSink(mock))
+
+	log.Warnw("cache miss", "key", "user:1", slog.Int("size", 3), 42, "dangling")
+
+	if mock.Len() != 1 {
+		t.Fatalf("expected 1 entry, got %d", mock.Len())
+	}
+	attrs := mock.entries[0].Attrs
+	want := []string{"key", "size", badKey}
+	if len(attrs) != len(want) {
+		t.Fatalf("expected %d attrs, got %v", len(want), attrs)
+	}
+	for i, key := range want {
+		if attrs[i].Key != key {
+			t.Errorf("attr %d: expected key %q, got %q", i, key, attrs[i].Key)
+		}
+	}
+	// Every stray argument is kept under the one !BADKEY field.
+	got, ok := mock.entries[0].Fields[badKey].([]any)
+	if !ok || len(got) != 2 || got[0] != 42 || got[1] != "dangling" {
+		t.Errorf("expected [42 dangling] under %s, got %v", badKey, mock.entries[0].Fields[badKey])
+	}
+
+	log.Infow("single", 1)
+	if got := mock.entries[1].Fields[badKey]; got != int64(1) {
+		t.Errorf("expected a single stray argument as is, got %#v", got)
+	}
+}
+
+func TestLevelSugar(t *testing.T) {
+	mock := &mockSink{}
+	log := New(WithLevel(TraceLevel), WithSink(mock))
+
+	log.Tracef("t %d", 1)
+	log.Tracew("t", "k", 1)
+	log.Noticef("n %d", 2)
+	log.Noticew("n", "k", 2)
+	log.Criticalf("c %d", 3)
+	log.Criticalw("c", "k", 3)
+
+	want := []string{"TRACE", "TRACE", "NOTICE", "NOTICE", "CRITICAL", "CRITICAL"}
+	if mock.Len() != len(want) {
+		t.Fatalf("expected %d entries, got %d", len(want), mock.Len())
+	}
+	for i, level := range want {
+		if got := mock.entries[i].Level; got != level {
+			t.Errorf("entry %d: expected level %s, got %s", i, level, got)
+		}
+	}
+}
+
+func TestAsyncFormattedMethods(t *testing.T) {
+	mock := &mockSink{}
+	async := NewAsync(New(WithSink(mock)))
+
+	async.Infof("processed %d items", 10)
+	async.Errorw("failed", "id", 7)
+	_ = async.Close()
+
+	if mock.Len() != 2 {
+		t.Fatalf("expected 2 entries, got %d", mock.Len())
+	}
+}
+
+func TestSampledLevelSugar(t *testing.T) {
+	mock := &mockSink{}
+	log := NewSampled(New(WithLevel(TraceLevel), WithSink(mock)), &SamplingConfig{Initial: 1, Interval: time.Hour})
+
+	for i := 0; i < 5; i++ {
+		log.Tracef("t %d", i)
+		log.Noticew("n", "i", i)
+		log.Criticalf("c %d", i)
+	}
+
+	// Trace and notice are sampled, critical always gets through.
+	if mock.Len() != 7 {
+		t.Errorf("expected 1 trace, 1 notice and 5 critical entries, got %d", mock.Len())
+	}
+}
+
+// gateSink blocks writes until its gate is closed.
+type gateSink struct {
+	mockSink
+	gate chan struct{}
+}
+
+func (g *gateSink) Write(entry *formatter.Entry) error {
+	<-g.gate
+	return g.mockSink.Write(entry)
+}
+
+func TestAsyncLogfIsAsync(t *testing.T) {
+	s := &gateSink{gate: make(chan struct{})}
+	async := NewAsync(New(WithSink(s)))
+
+	done := make(chan struct{})
+	go func() {
+		async.Logf(context.Background(), InfoLevel, "f %d", 1)
+		async.Logw(context.Background(), InfoLevel, "w", "k", 1)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected Logf and Logw to return before the sink writes")
+	}
+
+	close(s.gate)
+	_ = async.Close()
+	if s.Len() != 2 {
+		t.Errorf("expected 2 entries, got %d", s.Len())
+	}
+}